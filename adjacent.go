@@ -0,0 +1,168 @@
+package consume2
+
+// Group[K,T] is a run of consecutive T values sharing the same key.
+type Group[K, T any] struct {
+	Key    K
+	Values []T
+}
+
+// Run[T] is a run of consecutive equal T values.
+type Run[T any] struct {
+
+	// Value is the first T value of the run.
+	Value T
+
+	// Count is the number of T values in the run.
+	Count int
+}
+
+// DedupeAdjacent[T] returns a Consumer[T] that sends only the first T
+// value of each run of consecutive equal T values to the underlying
+// consumer. eq reports whether two T values are equal. On a sorted stream,
+// DedupeAdjacent[T] removes all duplicates using constant memory.
+func DedupeAdjacent[T any](
+	consumer Consumer[T], eq func(a, b T) bool) Consumer[T] {
+	return &dedupeAdjacentConsumer[T]{Consumer: consumer, eq: eq}
+}
+
+// GroupAdjacent[T,K] returns a Consumer[T] that sends each run of
+// consecutive T values with the same key to the underlying consumer as a
+// Group[K,T]. key returns the key of a T value. When finished, the
+// returned consumer sends the last run.
+func GroupAdjacent[T any, K comparable](
+	consumer Consumer[Group[K, T]], key func(T) K) Consumer[T] {
+	return &groupAdjacentConsumer[T, K]{consumer: consumer, key: key}
+}
+
+// RunLength[T] returns a Consumer[T] that sends each run of consecutive
+// equal T values to the underlying consumer as a Run[T]. eq reports
+// whether two T values are equal. When finished, the returned consumer
+// sends the last run.
+func RunLength[T any](
+	consumer Consumer[Run[T]], eq func(a, b T) bool) Consumer[T] {
+	return &runLengthConsumer[T]{consumer: consumer, eq: eq}
+}
+
+// PDedupeAdjacent returns a Pipeline that emits only the first T value of
+// each run of consecutive equal T values it receives. See DedupeAdjacent.
+func PDedupeAdjacent[T any](eq func(a, b T) bool) Pipeline[T, T] {
+	return func(inner Consumer[T]) Consumer[T] {
+		return DedupeAdjacent(inner, eq)
+	}
+}
+
+// PGroupAdjacent returns a Pipeline that emits each run of consecutive T
+// values with the same key that it receives as a Group[K,T]. See
+// GroupAdjacent.
+func PGroupAdjacent[T any, K comparable](
+	key func(T) K) Pipeline[T, Group[K, T]] {
+	return func(inner Consumer[Group[K, T]]) Consumer[T] {
+		return GroupAdjacent(inner, key)
+	}
+}
+
+// PRunLength returns a Pipeline that emits each run of consecutive equal
+// T values that it receives as a Run[T]. See RunLength.
+func PRunLength[T any](eq func(a, b T) bool) Pipeline[T, Run[T]] {
+	return func(inner Consumer[Run[T]]) Consumer[T] {
+		return RunLength(inner, eq)
+	}
+}
+
+type dedupeAdjacentConsumer[T any] struct {
+	Consumer[T]
+	eq      func(a, b T) bool
+	first   T
+	started bool
+}
+
+func (d *dedupeAdjacentConsumer[T]) Consume(value T) {
+	if d.started && d.eq(d.first, value) {
+		return
+	}
+	d.first = value
+	d.started = true
+	d.Consumer.Consume(value)
+}
+
+func (d *dedupeAdjacentConsumer[T]) Finish() {
+	Finish(d.Consumer)
+}
+
+func (d *dedupeAdjacentConsumer[T]) Err() error {
+	return Err(d.Consumer)
+}
+
+type groupAdjacentConsumer[T any, K comparable] struct {
+	consumer Consumer[Group[K, T]]
+	key      func(T) K
+	group    Group[K, T]
+}
+
+func (g *groupAdjacentConsumer[T, K]) CanConsume() bool {
+	return g.consumer.CanConsume()
+}
+
+func (g *groupAdjacentConsumer[T, K]) Consume(value T) {
+	if !g.consumer.CanConsume() {
+		return
+	}
+	key := g.key(value)
+	if len(g.group.Values) > 0 && g.group.Key != key {
+		g.emit()
+	}
+	g.group.Key = key
+	g.group.Values = append(g.group.Values, value)
+}
+
+func (g *groupAdjacentConsumer[T, K]) Finish() {
+	if len(g.group.Values) > 0 && g.consumer.CanConsume() {
+		g.emit()
+	}
+	Finish(g.consumer)
+}
+
+func (g *groupAdjacentConsumer[T, K]) Err() error {
+	return Err(g.consumer)
+}
+
+func (g *groupAdjacentConsumer[T, K]) emit() {
+	g.consumer.Consume(g.group)
+	g.group = Group[K, T]{}
+}
+
+type runLengthConsumer[T any] struct {
+	consumer Consumer[Run[T]]
+	eq       func(a, b T) bool
+	run      Run[T]
+}
+
+func (r *runLengthConsumer[T]) CanConsume() bool {
+	return r.consumer.CanConsume()
+}
+
+func (r *runLengthConsumer[T]) Consume(value T) {
+	if !r.consumer.CanConsume() {
+		return
+	}
+	if r.run.Count > 0 && r.eq(r.run.Value, value) {
+		r.run.Count++
+		return
+	}
+	if r.run.Count > 0 {
+		r.consumer.Consume(r.run)
+	}
+	r.run = Run[T]{Value: value, Count: 1}
+}
+
+func (r *runLengthConsumer[T]) Finish() {
+	if r.run.Count > 0 && r.consumer.CanConsume() {
+		r.consumer.Consume(r.run)
+	}
+	r.run = Run[T]{}
+	Finish(r.consumer)
+}
+
+func (r *runLengthConsumer[T]) Err() error {
+	return Err(r.consumer)
+}
@@ -0,0 +1,74 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPDedupeAdjacent(t *testing.T) {
+	assert := assert.New(t)
+	var result []int
+	consume2.FromSlice(
+		[]int{1, 1, 2, 3, 3, 3, 1, 4, 4},
+		consume2.PDedupeAdjacent(intEq).AppendTo(&result))
+	assert.Equal([]int{1, 2, 3, 1, 4}, result)
+}
+
+func TestPGroupAdjacent(t *testing.T) {
+	assert := assert.New(t)
+	var result []consume2.Group[int, string]
+	pipeline := consume2.PGroupAdjacent(func(s string) int { return len(s) })
+	consume2.FromSlice(
+		[]string{"a", "b", "cd", "ef", "gh", "i", "jkl"},
+		pipeline.AppendTo(&result))
+	assert.Equal(
+		[]consume2.Group[int, string]{
+			{Key: 1, Values: []string{"a", "b"}},
+			{Key: 2, Values: []string{"cd", "ef", "gh"}},
+			{Key: 1, Values: []string{"i"}},
+			{Key: 3, Values: []string{"jkl"}},
+		},
+		result)
+}
+
+func TestPGroupAdjacentInnerFinishes(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.Join(
+		consume2.PGroupAdjacent(func(x int) int { return x / 3 }),
+		consume2.PSlice[consume2.Group[int, int]](0, 2))
+	var result []consume2.Group[int, int]
+	consumer := pipeline.AppendTo(&result)
+	feedInts(consumer)
+	consume2.Finish(consumer)
+	assert.Equal(
+		[]consume2.Group[int, int]{
+			{Key: 0, Values: []int{0, 1, 2}},
+			{Key: 1, Values: []int{3, 4, 5}},
+		},
+		result)
+}
+
+func TestPRunLength(t *testing.T) {
+	assert := assert.New(t)
+	var result []consume2.Run[int]
+	consume2.FromSlice(
+		[]int{7, 7, 7, 2, 7, 5, 5},
+		consume2.PRunLength(intEq).AppendTo(&result))
+	assert.Equal(
+		[]consume2.Run[int]{
+			{Value: 7, Count: 3},
+			{Value: 2, Count: 1},
+			{Value: 7, Count: 1},
+			{Value: 5, Count: 2},
+		},
+		result)
+	result = nil
+	consume2.FromSlice(nil, consume2.PRunLength(intEq).AppendTo(&result))
+	assert.Empty(result)
+}
+
+func intEq(a, b int) bool {
+	return a == b
+}
@@ -0,0 +1,143 @@
+package consume2
+
+// Number is a constraint that permits any integer or floating point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Counter[T] is a Consumer[T] that counts the T values it consumes.
+// The zero value of Counter[T] is ready to use.
+type Counter[T any] struct {
+	count int
+}
+
+// CanConsume always returns true.
+func (c *Counter[T]) CanConsume() bool {
+	return true
+}
+
+// Consume counts value.
+func (c *Counter[T]) Consume(value T) {
+	c.count++
+}
+
+// Result returns the number of T values consumed.
+func (c *Counter[T]) Result() int {
+	return c.count
+}
+
+// Summer[N] is a Consumer[N] that sums the N values it consumes.
+// The zero value of Summer[N] is ready to use.
+type Summer[N Number] struct {
+	sum N
+}
+
+// CanConsume always returns true.
+func (s *Summer[N]) CanConsume() bool {
+	return true
+}
+
+// Consume adds value to the sum.
+func (s *Summer[N]) Consume(value N) {
+	s.sum += value
+}
+
+// Result returns the sum of the N values consumed.
+func (s *Summer[N]) Result() N {
+	return s.sum
+}
+
+// MinMax[T] is a Consumer[T] that finds the smallest and largest T values
+// it consumes.
+type MinMax[T any] struct {
+	less func(a, b T) bool
+	min  T
+	max  T
+	ok   bool
+}
+
+// NewMinMax[T] creates a MinMax[T]. less reports whether a is less than b.
+func NewMinMax[T any](less func(a, b T) bool) *MinMax[T] {
+	return &MinMax[T]{less: less}
+}
+
+// CanConsume always returns true.
+func (m *MinMax[T]) CanConsume() bool {
+	return true
+}
+
+// Consume compares value to the smallest and largest T values so far.
+func (m *MinMax[T]) Consume(value T) {
+	if !m.ok {
+		m.min, m.max, m.ok = value, value, true
+		return
+	}
+	if m.less(value, m.min) {
+		m.min = value
+	}
+	if m.less(m.max, value) {
+		m.max = value
+	}
+}
+
+// Result returns the smallest and largest T values consumed. When there
+// are ties, Result returns the first one consumed. ok is false if no T
+// values were consumed.
+func (m *MinMax[T]) Result() (min, max T, ok bool) {
+	return m.min, m.max, m.ok
+}
+
+// Mean[N] is a Consumer[N] that computes the arithmetic mean of the N
+// values it consumes. The zero value of Mean[N] is ready to use.
+type Mean[N Number] struct {
+	count int
+	mean  float64
+}
+
+// CanConsume always returns true.
+func (m *Mean[N]) CanConsume() bool {
+	return true
+}
+
+// Consume includes value in the mean.
+func (m *Mean[N]) Consume(value N) {
+	m.count++
+	m.mean += (float64(value) - m.mean) / float64(m.count)
+}
+
+// Result returns the mean of the N values consumed. ok is false if no N
+// values were consumed.
+func (m *Mean[N]) Result() (mean float64, ok bool) {
+	return m.mean, m.count > 0
+}
+
+// Reducer[T,A] is a Consumer[T] that folds the T values it consumes into
+// a single A value.
+type Reducer[T, A any] struct {
+	acc  A
+	step func(A, T) A
+}
+
+// Reduce[T,A] creates a Reducer[T,A]. For each T value, the returned
+// reducer replaces its accumulator with step(acc, value). The accumulator
+// starts out as init.
+func Reduce[T, A any](init A, step func(A, T) A) *Reducer[T, A] {
+	return &Reducer[T, A]{acc: init, step: step}
+}
+
+// CanConsume always returns true.
+func (r *Reducer[T, A]) CanConsume() bool {
+	return true
+}
+
+// Consume folds value into the accumulator.
+func (r *Reducer[T, A]) Consume(value T) {
+	r.acc = r.step(r.acc, value)
+}
+
+// Result returns the accumulator.
+func (r *Reducer[T, A]) Result() A {
+	return r.acc
+}
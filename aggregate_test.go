@@ -0,0 +1,73 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregates(t *testing.T) {
+	assert := assert.New(t)
+	var counter consume2.Counter[int]
+	var summer consume2.Summer[int]
+	var mean consume2.Mean[int]
+	minMax := consume2.NewMinMax(func(a, b int) bool { return a < b })
+	consume2.FromSlice(
+		[]int{5, 3, 9, 2, 6},
+		consume2.Compose[int](&counter, &summer, &mean, minMax))
+	assert.Equal(5, counter.Result())
+	assert.Equal(25, summer.Result())
+	average, ok := mean.Result()
+	assert.True(ok)
+	assert.Equal(5.0, average)
+	min, max, ok := minMax.Result()
+	assert.True(ok)
+	assert.Equal(2, min)
+	assert.Equal(9, max)
+}
+
+func TestAggregatesEmpty(t *testing.T) {
+	assert := assert.New(t)
+	var counter consume2.Counter[string]
+	var summer consume2.Summer[float64]
+	var mean consume2.Mean[float64]
+	minMax := consume2.NewMinMax(func(a, b string) bool { return a < b })
+	assert.Zero(counter.Result())
+	assert.Zero(summer.Result())
+	_, ok := mean.Result()
+	assert.False(ok)
+	_, _, ok = minMax.Result()
+	assert.False(ok)
+}
+
+func TestMinMaxTies(t *testing.T) {
+	assert := assert.New(t)
+	minMax := consume2.NewMinMax(func(a, b person) bool { return a.Age < b.Age })
+	consume2.FromSlice([]person{
+		{Name: "a", Age: 3},
+		{Name: "b", Age: 1},
+		{Name: "c", Age: 1},
+		{Name: "d", Age: 3},
+	}, consume2.Consumer[person](minMax))
+	min, max, _ := minMax.Result()
+	assert.Equal("b", min.Name)
+	assert.Equal("a", max.Name)
+}
+
+func TestReduce(t *testing.T) {
+	assert := assert.New(t)
+	totalAge := consume2.Reduce(
+		0, func(acc int, p person) int { return acc + p.Age })
+	lastFiftyPlus := consume2.Reduce(
+		"", func(acc string, p person) string {
+			if p.Age >= 50 {
+				return p.Name
+			}
+			return acc
+		})
+	consume2.FromSlice(
+		people, consume2.Compose[person](totalAge, lastFiftyPlus))
+	assert.Equal(218, totalAge.Result())
+	assert.Equal("Beth", lastFiftyPlus.Result())
+}
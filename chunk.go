@@ -0,0 +1,116 @@
+package consume2
+
+// Chunk[T] returns a Consumer[T] that groups consecutive T values into
+// slices of length n and sends those slices to the underlying consumer.
+// When finished, the returned consumer sends the remaining T values as a
+// final, shorter slice. Chunk[T] panics if n <= 0.
+func Chunk[T any](consumer Consumer[[]T], n int) Consumer[T] {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	return &chunkConsumer[T]{consumer: consumer, maxCount: n}
+}
+
+// ChunkWeight[T] works like Chunk[T] except that it also limits the total
+// weight of each slice. weight returns the weight of a T value such as
+// its size in bytes. A slice ends before the T value that would make its
+// total weight exceed maxWeight or when it has maxCount T values, whichever
+// comes first. A T value that weighs more than maxWeight by itself goes in
+// a slice of its own. ChunkWeight[T] panics if maxCount <= 0 or
+// maxWeight <= 0.
+func ChunkWeight[T any](
+	consumer Consumer[[]T],
+	maxCount int,
+	maxWeight int,
+	weight func(T) int) Consumer[T] {
+	if maxCount <= 0 {
+		panic("maxCount must be positive")
+	}
+	if maxWeight <= 0 {
+		panic("maxWeight must be positive")
+	}
+	return &chunkConsumer[T]{
+		consumer:  consumer,
+		maxCount:  maxCount,
+		maxWeight: maxWeight,
+		weight:    weight,
+	}
+}
+
+// PChunk returns a Pipeline that emits the T values it receives in slices
+// of length n. The last slice may be shorter. See Chunk.
+func PChunk[T any](n int) Pipeline[T, []T] {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	return func(inner Consumer[[]T]) Consumer[T] {
+		return Chunk(inner, n)
+	}
+}
+
+// PChunkWeight returns a Pipeline that emits the T values it receives in
+// slices limited by both count and total weight. See ChunkWeight.
+func PChunkWeight[T any](
+	maxCount int, maxWeight int, weight func(T) int) Pipeline[T, []T] {
+	if maxCount <= 0 {
+		panic("maxCount must be positive")
+	}
+	if maxWeight <= 0 {
+		panic("maxWeight must be positive")
+	}
+	return func(inner Consumer[[]T]) Consumer[T] {
+		return ChunkWeight(inner, maxCount, maxWeight, weight)
+	}
+}
+
+type chunkConsumer[T any] struct {
+	consumer    Consumer[[]T]
+	maxCount    int
+	maxWeight   int
+	weight      func(T) int
+	chunk       []T
+	chunkWeight int
+}
+
+func (c *chunkConsumer[T]) CanConsume() bool {
+	return c.consumer.CanConsume()
+}
+
+func (c *chunkConsumer[T]) Consume(value T) {
+	if !c.consumer.CanConsume() {
+		return
+	}
+	if c.weight != nil {
+		w := c.weight(value)
+		if len(c.chunk) > 0 && c.chunkWeight+w > c.maxWeight {
+			c.emit()
+		}
+		c.chunkWeight += w
+	}
+	if c.chunk == nil && c.weight == nil {
+		c.chunk = make([]T, 0, c.maxCount)
+	}
+	c.chunk = append(c.chunk, value)
+	if len(c.chunk) == c.maxCount ||
+		(c.weight != nil && c.chunkWeight >= c.maxWeight) {
+		c.emit()
+	}
+}
+
+func (c *chunkConsumer[T]) Finish() {
+	if len(c.chunk) > 0 && c.consumer.CanConsume() {
+		c.emit()
+	}
+	c.chunk = nil
+	Finish(c.consumer)
+}
+
+func (c *chunkConsumer[T]) Err() error {
+	return Err(c.consumer)
+}
+
+func (c *chunkConsumer[T]) emit() {
+	c.consumer.Consume(c.chunk)
+	c.chunk = nil
+	c.chunkWeight = 0
+}
@@ -0,0 +1,65 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPChunk(t *testing.T) {
+	assert := assert.New(t)
+	var chunks [][]int
+	err := consume2.FromSlice(
+		[]int{1, 2, 3, 4, 5, 6, 7},
+		consume2.PChunk[int](3).AppendTo(&chunks))
+	assert.NoError(err)
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}, {7}}, chunks)
+}
+
+func TestPChunkExact(t *testing.T) {
+	assert := assert.New(t)
+	var chunks [][]int
+	consume2.FromSlice(
+		[]int{1, 2, 3, 4}, consume2.PChunk[int](2).AppendTo(&chunks))
+	assert.Equal([][]int{{1, 2}, {3, 4}}, chunks)
+	chunks = nil
+	consume2.FromSlice(nil, consume2.PChunk[int](2).AppendTo(&chunks))
+	assert.Empty(chunks)
+}
+
+func TestPChunkInnerFinishes(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.Join(
+		consume2.PChunk[int](2), consume2.PSlice[[]int](0, 2))
+	var chunks [][]int
+	consumer := pipeline.AppendTo(&chunks)
+	feedInts(consumer)
+	consume2.Finish(consumer)
+	assert.Equal([][]int{{0, 1}, {2, 3}}, chunks)
+}
+
+func TestPChunkWeight(t *testing.T) {
+	assert := assert.New(t)
+	var chunks [][]string
+	pipeline := consume2.PChunkWeight(
+		3, 10, func(s string) int { return len(s) })
+	consume2.FromSlice(
+		[]string{"abc", "defg", "hi", "jklmnopqrstu", "v", "w", "x", "y"},
+		pipeline.AppendTo(&chunks))
+	assert.Equal(
+		[][]string{
+			{"abc", "defg", "hi"},
+			{"jklmnopqrstu"},
+			{"v", "w", "x"},
+			{"y"},
+		},
+		chunks)
+}
+
+func TestChunkPanics(t *testing.T) {
+	weight := func(x int) int { return x }
+	assert.Panics(t, func() { consume2.PChunk[int](0) })
+	assert.Panics(t, func() { consume2.PChunkWeight(0, 5, weight) })
+	assert.Panics(t, func() { consume2.PChunkWeight(5, 0, weight) })
+}
@@ -0,0 +1,78 @@
+package consume2
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// Encoder[T] writes T values to a stream.
+type Encoder[T any] interface {
+	Encode(value T) error
+}
+
+// Decoder[T] reads T values from a stream. Decode returns io.EOF when
+// there are no more T values to read.
+type Decoder[T any] interface {
+	Decode(ptr *T) error
+}
+
+// Codec[T] creates Encoder[T] and Decoder[T] instances. ExternalSort uses
+// a Codec[T] to write T values to temporary files and read them back.
+type Codec[T any] interface {
+	NewEncoder(w io.Writer) Encoder[T]
+	NewDecoder(r io.Reader) Decoder[T]
+}
+
+// GobCodec[T] returns a Codec[T] that uses encoding/gob.
+func GobCodec[T any]() Codec[T] {
+	return stdCodec[T]{
+		newEncoder: func(w io.Writer) anyEncoder { return gob.NewEncoder(w) },
+		newDecoder: func(r io.Reader) anyDecoder { return gob.NewDecoder(r) },
+	}
+}
+
+// JSONCodec[T] returns a Codec[T] that uses encoding/json.
+func JSONCodec[T any]() Codec[T] {
+	return stdCodec[T]{
+		newEncoder: func(w io.Writer) anyEncoder { return json.NewEncoder(w) },
+		newDecoder: func(r io.Reader) anyDecoder { return json.NewDecoder(r) },
+	}
+}
+
+type anyEncoder interface {
+	Encode(value any) error
+}
+
+type anyDecoder interface {
+	Decode(ptr any) error
+}
+
+type stdCodec[T any] struct {
+	newEncoder func(w io.Writer) anyEncoder
+	newDecoder func(r io.Reader) anyDecoder
+}
+
+func (s stdCodec[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return stdEncoder[T]{encoder: s.newEncoder(w)}
+}
+
+func (s stdCodec[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return stdDecoder[T]{decoder: s.newDecoder(r)}
+}
+
+type stdEncoder[T any] struct {
+	encoder anyEncoder
+}
+
+func (s stdEncoder[T]) Encode(value T) error {
+	return s.encoder.Encode(&value)
+}
+
+type stdDecoder[T any] struct {
+	decoder anyDecoder
+}
+
+func (s stdDecoder[T]) Decode(ptr *T) error {
+	return s.decoder.Decode(ptr)
+}
@@ -1,6 +1,10 @@
 // Package consume2 builds pipelines that consume values using Go generics.
 package consume2
 
+import (
+	"context"
+)
+
 // Consumer[T] consumes values of type T.
 type Consumer[T any] interface {
 
@@ -13,6 +17,46 @@ type Consumer[T any] interface {
 	Consume(value T)
 }
 
+// Finisher is implemented by consumers that need to know when there are no
+// more values to consume, such as consumers that sort or batch values.
+// Sources such as FromSlice and FromGenerator call Finish once after
+// sending their last value. Consumers that wrap other consumers pass
+// Finish on to the consumers they wrap.
+type Finisher interface {
+
+	// Finish signals that there are no more values to consume. Finish is
+	// called even if CanConsume already returned false.
+	Finish()
+}
+
+// Finish calls the Finish method of consumer if consumer implements
+// Finisher. Otherwise Finish does nothing.
+func Finish[T any](consumer Consumer[T]) {
+	if f, ok := consumer.(Finisher); ok {
+		f.Finish()
+	}
+}
+
+// ErrConsumer[T] is a Consumer[T] that can fail. Once an ErrConsumer[T]
+// encounters an error, its CanConsume method returns false. Consumers that
+// wrap other consumers pass Err on to the consumers they wrap.
+type ErrConsumer[T any] interface {
+	Consumer[T]
+
+	// Err returns the first error this consumer encountered or nil if it
+	// encountered no error.
+	Err() error
+}
+
+// Err returns the error that consumer encountered if consumer implements
+// ErrConsumer[T]. Otherwise Err returns nil.
+func Err[T any](consumer Consumer[T]) error {
+	if e, ok := consumer.(ErrConsumer[T]); ok {
+		return e.Err()
+	}
+	return nil
+}
+
 // AsFunc converts a Consumer into a function that consumes its paramter
 // and returns false when no more values can be consumed. AsFunc allows
 // interoperability with other go packages such as github.com/google/btree.
@@ -131,11 +175,70 @@ func MaybeMap[T, U any](
 	return &maybeMapConsumer[T, U]{Consumer: consumer, mapper: mapper}
 }
 
+// Scan[T,A] returns a Consumer[T] that folds each T value it consumes into
+// an accumulator using step and sends the accumulator after each T value
+// to the underlying consumer. The accumulator starts out as init.
+func Scan[T, A any](
+	consumer Consumer[A], init A, step func(A, T) A) Consumer[T] {
+	return &scanConsumer[T, A]{Consumer: consumer, acc: init, step: step}
+}
+
+// FlatMap[T,U] returns a Consumer[T] that applies a mapper function to the
+// T value being consumed and sends each of the resulting U values to the
+// underlying consumer. FlatMap[T,U] stops sending U values partway
+// through if the underlying consumer can consume no more values.
+func FlatMap[T, U any](
+	consumer Consumer[U], mapper func(T) []U) Consumer[T] {
+	return &flatMapConsumer[T, U]{Consumer: consumer, mapper: mapper}
+}
+
+// Expand[T,U] works like FlatMap[T,U] except that instead of returning a
+// slice of U values, the emit function sends them to the consumer passed
+// to it. emit should stop sending U values when the CanConsume method of
+// that consumer returns false.
+func Expand[T, U any](
+	consumer Consumer[U],
+	emit func(value T, consumer Consumer[U])) Consumer[T] {
+	return &expandConsumer[T, U]{Consumer: consumer, emit: emit}
+}
+
+// Flatten[T] returns a Consumer[[]T] that sends each T value in the
+// slices it consumes to the underlying consumer.
+func Flatten[T any](consumer Consumer[T]) Consumer[[]T] {
+	return FlatMap(consumer, func(values []T) []T { return values })
+}
+
+// MapErr[T,U] works like Map[T,U] except that the mapper function can fail.
+// When the mapper function returns an error, the returned consumer stops
+// consuming values and Err reports that error.
+func MapErr[T, U any](
+	consumer Consumer[U], mapper func(T) (U, error)) Consumer[T] {
+	return &mapErrConsumer[T, U]{consumer: consumer, mapper: mapper}
+}
+
+// FilterErr[T] works like Filter[T] except that the filter function can
+// fail. When the filter function returns an error, the returned consumer
+// stops consuming values and Err reports that error.
+func FilterErr[T any](
+	consumer Consumer[T], filter func(value T) (bool, error)) Consumer[T] {
+	return &filterErrConsumer[T]{consumer: consumer, filter: filter}
+}
+
+// WithContext[T] returns a Consumer[T] that sends the values it consumes to
+// the underlying consumer until ctx is done. Once ctx is done, the
+// CanConsume method of returned consumer returns false, and Err reports
+// ctx.Err().
+func WithContext[T any](
+	ctx context.Context, consumer Consumer[T]) Consumer[T] {
+	return &contextConsumer[T]{ctx: ctx, consumer: consumer}
+}
+
 // Compose[T] returns all the Consumer[T] values passed to it as a single
 // Consumer[T]. When returned consumer consumes a value, all the passed in
 // consumers consume that same value. The CanConsume method of returned
 // consumer returns false when the CanConsume method of all the passed in
-// consumers returns false.
+// consumers returns false. The Err method of returned consumer reports the
+// first error found among the passed in consumers.
 func Compose[T any](consumers ...Consumer[T]) Consumer[T] {
 	switch length := len(consumers); length {
 	case 0:
@@ -145,10 +248,53 @@ func Compose[T any](consumers ...Consumer[T]) Consumer[T] {
 	default:
 		consumerList := make([]Consumer[T], length)
 		copy(consumerList, consumers)
-		return &multiConsumer[T]{consumers: consumerList}
+		activeList := make([]Consumer[T], length)
+		copy(activeList, consumers)
+		return &multiConsumer[T]{consumers: activeList, all: consumerList}
 	}
 }
 
+// Tee[T] returns a Consumer[T] that sends each value it consumes to both
+// consumer and side. Unlike Compose[T], the CanConsume method of returned
+// consumer follows only consumer. If side can consume no more values
+// first, the returned consumer keeps sending values to consumer alone.
+// The Err method of returned consumer reports the error of consumer or
+// else the error of side.
+func Tee[T any](consumer, side Consumer[T]) Consumer[T] {
+	return &teeConsumer[T]{consumer: consumer, side: side}
+}
+
+// Switch[T] returns a Consumer[T] that sends each value it consumes to
+// exactly one of the passed in consumers. selector returns the zero based
+// index of the consumer that gets a value. If selector returns an index
+// that is out of range or the selected consumer can consume no more
+// values, the value is dropped. The CanConsume method of returned consumer
+// returns false when the CanConsume method of all the passed in consumers
+// returns false. The Err method of returned consumer reports the first
+// error found among the passed in consumers.
+func Switch[T any](
+	selector func(value T) int, consumers ...Consumer[T]) Consumer[T] {
+	consumerList := make([]Consumer[T], len(consumers))
+	copy(consumerList, consumers)
+	return &switchConsumer[T]{selector: selector, consumers: consumerList}
+}
+
+// Partition[T] returns a Consumer[T] that sends the values for which
+// filter returns true to yes and the rest to no. Partition[T] works like
+// Switch[T] with two consumers.
+func Partition[T any](
+	filter func(value T) bool, yes, no Consumer[T]) Consumer[T] {
+	return Switch(
+		func(value T) int {
+			if filter(value) {
+				return 0
+			}
+			return 1
+		},
+		yes,
+		no)
+}
+
 // PageBuilder[T] is a Consumer[T] that builds a specific page of T values.
 // It consumes just enough T values needed to build the desired page.
 type PageBuilder[T any] struct {
@@ -190,6 +336,11 @@ func (p *PageBuilder[T]) Consume(value T) {
 	p.consumer.Consume(value)
 }
 
+// Finish signals that there are no more T values to consume.
+func (p *PageBuilder[T]) Finish() {
+	Finish(p.consumer)
+}
+
 // Build builds the desired page of T values. morePages is true if there
 // are more pages after the desired page. Build is called after this
 // builder has consumed its T values.
@@ -247,6 +398,14 @@ func (s *sliceConsumer[T]) Consume(value T) {
 	s.idx++
 }
 
+func (s *sliceConsumer[T]) Finish() {
+	Finish(s.consumer)
+}
+
+func (s *sliceConsumer[T]) Err() error {
+	return Err(s.consumer)
+}
+
 type filterConsumer[T any] struct {
 	Consumer[T]
 	filter func(value T) bool
@@ -258,6 +417,14 @@ func (f *filterConsumer[T]) Consume(value T) {
 	}
 }
 
+func (f *filterConsumer[T]) Finish() {
+	Finish(f.Consumer)
+}
+
+func (f *filterConsumer[T]) Err() error {
+	return Err(f.Consumer)
+}
+
 type filterpConsumer[T any] struct {
 	Consumer[T]
 	filter func(ptr *T) bool
@@ -269,6 +436,14 @@ func (f *filterpConsumer[T]) Consume(value T) {
 	}
 }
 
+func (f *filterpConsumer[T]) Finish() {
+	Finish(f.Consumer)
+}
+
+func (f *filterpConsumer[T]) Err() error {
+	return Err(f.Consumer)
+}
+
 type mapConsumer[T, U any] struct {
 	Consumer[U]
 	mapper func(T) U
@@ -278,6 +453,14 @@ func (m *mapConsumer[T, U]) Consume(value T) {
 	m.Consumer.Consume(m.mapper(value))
 }
 
+func (m *mapConsumer[T, U]) Finish() {
+	Finish(m.Consumer)
+}
+
+func (m *mapConsumer[T, U]) Err() error {
+	return Err(m.Consumer)
+}
+
 type maybeMapConsumer[T, U any] struct {
 	Consumer[U]
 	mapper func(T) (U, bool)
@@ -289,8 +472,177 @@ func (m *maybeMapConsumer[T, U]) Consume(value T) {
 	}
 }
 
+func (m *maybeMapConsumer[T, U]) Finish() {
+	Finish(m.Consumer)
+}
+
+func (m *maybeMapConsumer[T, U]) Err() error {
+	return Err(m.Consumer)
+}
+
+type scanConsumer[T, A any] struct {
+	Consumer[A]
+	acc  A
+	step func(A, T) A
+}
+
+func (s *scanConsumer[T, A]) Consume(value T) {
+	s.acc = s.step(s.acc, value)
+	s.Consumer.Consume(s.acc)
+}
+
+func (s *scanConsumer[T, A]) Finish() {
+	Finish(s.Consumer)
+}
+
+func (s *scanConsumer[T, A]) Err() error {
+	return Err(s.Consumer)
+}
+
+type flatMapConsumer[T, U any] struct {
+	Consumer[U]
+	mapper func(T) []U
+}
+
+func (f *flatMapConsumer[T, U]) Consume(value T) {
+	for _, mvalue := range f.mapper(value) {
+		if !f.Consumer.CanConsume() {
+			return
+		}
+		f.Consumer.Consume(mvalue)
+	}
+}
+
+func (f *flatMapConsumer[T, U]) Finish() {
+	Finish(f.Consumer)
+}
+
+func (f *flatMapConsumer[T, U]) Err() error {
+	return Err(f.Consumer)
+}
+
+type expandConsumer[T, U any] struct {
+	Consumer[U]
+	emit func(value T, consumer Consumer[U])
+}
+
+func (e *expandConsumer[T, U]) Consume(value T) {
+	e.emit(value, e.Consumer)
+}
+
+func (e *expandConsumer[T, U]) Finish() {
+	Finish(e.Consumer)
+}
+
+func (e *expandConsumer[T, U]) Err() error {
+	return Err(e.Consumer)
+}
+
+type mapErrConsumer[T, U any] struct {
+	consumer Consumer[U]
+	mapper   func(T) (U, error)
+	err      error
+}
+
+func (m *mapErrConsumer[T, U]) CanConsume() bool {
+	return m.err == nil && m.consumer.CanConsume()
+}
+
+func (m *mapErrConsumer[T, U]) Consume(value T) {
+	if m.err != nil {
+		return
+	}
+	mvalue, err := m.mapper(value)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.consumer.Consume(mvalue)
+}
+
+func (m *mapErrConsumer[T, U]) Finish() {
+	Finish(m.consumer)
+}
+
+func (m *mapErrConsumer[T, U]) Err() error {
+	if m.err != nil {
+		return m.err
+	}
+	return Err(m.consumer)
+}
+
+type filterErrConsumer[T any] struct {
+	consumer Consumer[T]
+	filter   func(value T) (bool, error)
+	err      error
+}
+
+func (f *filterErrConsumer[T]) CanConsume() bool {
+	return f.err == nil && f.consumer.CanConsume()
+}
+
+func (f *filterErrConsumer[T]) Consume(value T) {
+	if f.err != nil {
+		return
+	}
+	ok, err := f.filter(value)
+	if err != nil {
+		f.err = err
+		return
+	}
+	if ok {
+		f.consumer.Consume(value)
+	}
+}
+
+func (f *filterErrConsumer[T]) Finish() {
+	Finish(f.consumer)
+}
+
+func (f *filterErrConsumer[T]) Err() error {
+	if f.err != nil {
+		return f.err
+	}
+	return Err(f.consumer)
+}
+
+type contextConsumer[T any] struct {
+	ctx      context.Context
+	consumer Consumer[T]
+	err      error
+}
+
+func (c *contextConsumer[T]) CanConsume() bool {
+	if c.err != nil {
+		return false
+	}
+	if err := c.ctx.Err(); err != nil {
+		c.err = err
+		return false
+	}
+	return c.consumer.CanConsume()
+}
+
+func (c *contextConsumer[T]) Consume(value T) {
+	if c.CanConsume() {
+		c.consumer.Consume(value)
+	}
+}
+
+func (c *contextConsumer[T]) Finish() {
+	Finish(c.consumer)
+}
+
+func (c *contextConsumer[T]) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return Err(c.consumer)
+}
+
 type multiConsumer[T any] struct {
 	consumers []Consumer[T]
+	all       []Consumer[T]
 }
 
 func (m *multiConsumer[T]) CanConsume() bool {
@@ -304,6 +656,21 @@ func (m *multiConsumer[T]) Consume(value T) {
 	}
 }
 
+func (m *multiConsumer[T]) Finish() {
+	for _, consumer := range m.all {
+		Finish(consumer)
+	}
+}
+
+func (m *multiConsumer[T]) Err() error {
+	for _, consumer := range m.all {
+		if err := Err(consumer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *multiConsumer[T]) filterFinished() {
 	idx := 0
 	for i := range m.consumers {
@@ -318,6 +685,76 @@ func (m *multiConsumer[T]) filterFinished() {
 	m.consumers = m.consumers[0:idx]
 }
 
+type teeConsumer[T any] struct {
+	consumer Consumer[T]
+	side     Consumer[T]
+}
+
+func (t *teeConsumer[T]) CanConsume() bool {
+	return t.consumer.CanConsume()
+}
+
+func (t *teeConsumer[T]) Consume(value T) {
+	if !t.consumer.CanConsume() {
+		return
+	}
+	if t.side.CanConsume() {
+		t.side.Consume(value)
+	}
+	t.consumer.Consume(value)
+}
+
+func (t *teeConsumer[T]) Finish() {
+	Finish(t.side)
+	Finish(t.consumer)
+}
+
+func (t *teeConsumer[T]) Err() error {
+	if err := Err(t.consumer); err != nil {
+		return err
+	}
+	return Err(t.side)
+}
+
+type switchConsumer[T any] struct {
+	selector  func(value T) int
+	consumers []Consumer[T]
+}
+
+func (s *switchConsumer[T]) CanConsume() bool {
+	for _, consumer := range s.consumers {
+		if consumer.CanConsume() {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *switchConsumer[T]) Consume(value T) {
+	index := s.selector(value)
+	if index < 0 || index >= len(s.consumers) {
+		return
+	}
+	if s.consumers[index].CanConsume() {
+		s.consumers[index].Consume(value)
+	}
+}
+
+func (s *switchConsumer[T]) Finish() {
+	for _, consumer := range s.consumers {
+		Finish(consumer)
+	}
+}
+
+func (s *switchConsumer[T]) Err() error {
+	for _, consumer := range s.consumers {
+		if err := Err(consumer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type nilConsumer[T any] struct {
 }
 
@@ -349,6 +786,14 @@ func (t *takeWhileConsumer[T]) Consume(value T) {
 	t.consumer.Consume(value)
 }
 
+func (t *takeWhileConsumer[T]) Finish() {
+	Finish(t.consumer)
+}
+
+func (t *takeWhileConsumer[T]) Err() error {
+	return Err(t.consumer)
+}
+
 func trueFunc[T any](value T) bool {
 	return true
 }
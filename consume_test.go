@@ -1,6 +1,8 @@
 package consume2_test
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"testing"
 
@@ -167,6 +169,65 @@ func TestComposeDefensiveCopy(t *testing.T) {
 	assert.Equal([]int{0}, y)
 }
 
+func TestTee(t *testing.T) {
+	assert := assert.New(t)
+	var main, side []int
+	consumer := consume2.Tee(
+		consume2.Slice(consume2.AppendTo(&main), 0, 4),
+		consume2.Slice(consume2.AppendTo(&side), 0, 2))
+	feedInts(consumer)
+	assert.Equal([]int{0, 1, 2, 3}, main)
+	assert.Equal([]int{0, 1}, side)
+}
+
+func TestTeeMainFinishesFirst(t *testing.T) {
+	assert := assert.New(t)
+	var main []int
+	var side finishTracker[int]
+	consumer := consume2.Tee[int](
+		consume2.Slice(consume2.AppendTo(&main), 0, 2), &side)
+	feedInts(consumer)
+	consume2.Finish(consumer)
+	assert.Equal([]int{0, 1}, main)
+	assert.Equal([]int{0, 1}, side.values)
+	assert.Equal(1, side.finished)
+}
+
+func TestPartition(t *testing.T) {
+	assert := assert.New(t)
+	var evens, odds []int
+	consumer := consume2.Partition(
+		func(value int) bool { return value%2 == 0 },
+		consume2.Slice(consume2.AppendTo(&evens), 0, 2),
+		consume2.Slice(consume2.AppendTo(&odds), 0, 4))
+	feedInts(consumer)
+	assert.Equal([]int{0, 2}, evens)
+	assert.Equal([]int{1, 3, 5, 7}, odds)
+}
+
+func TestSwitch(t *testing.T) {
+	assert := assert.New(t)
+	var zeros, ones []int
+	var tracker finishTracker[int]
+	consumer := consume2.Switch(
+		func(value int) int { return value % 4 },
+		consume2.Slice(consume2.AppendTo(&zeros), 0, 2),
+		consume2.Slice(consume2.AppendTo(&ones), 0, 3),
+		consume2.Slice[int](&tracker, 0, 1))
+	feedInts(consumer)
+	consume2.Finish(consumer)
+	assert.Equal([]int{0, 4}, zeros)
+	assert.Equal([]int{1, 5, 9}, ones)
+	assert.Equal([]int{2}, tracker.values)
+	assert.Equal(1, tracker.finished)
+}
+
+func TestSwitchEmpty(t *testing.T) {
+	consumer := consume2.Switch(func(value int) int { return 0 })
+	assert.False(t, consumer.CanConsume())
+	consumer.Consume(3)
+}
+
 func TestSlice(t *testing.T) {
 	assert := assert.New(t)
 	var threeToSeven []int
@@ -221,6 +282,42 @@ func TestMap(t *testing.T) {
 	assert.Equal([]string{"0", "1", "2", "3", "4"}, zeroTo5)
 }
 
+func TestFlatMap(t *testing.T) {
+	assert := assert.New(t)
+	var result []int
+	consumer := consume2.FlatMap(
+		consume2.Slice(consume2.AppendTo(&result), 0, 7),
+		func(value int) []int {
+			return []int{value, value, value}
+		})
+	feedInts(consumer)
+	assert.Equal([]int{0, 0, 0, 1, 1, 1, 2}, result)
+}
+
+func TestExpand(t *testing.T) {
+	assert := assert.New(t)
+	var result []string
+	consumer := consume2.Expand(
+		consume2.Slice(consume2.AppendTo(&result), 0, 5),
+		func(value int, inner consume2.Consumer[string]) {
+			for i := 0; i < value && inner.CanConsume(); i++ {
+				inner.Consume(strconv.Itoa(value))
+			}
+		})
+	feedInts(consumer)
+	assert.Equal([]string{"1", "2", "2", "3", "3"}, result)
+}
+
+func TestFlatten(t *testing.T) {
+	assert := assert.New(t)
+	var result []int
+	consumer := consume2.Flatten(
+		consume2.Slice(consume2.AppendTo(&result), 0, 4))
+	consume2.FromSlice(
+		[][]int{{1, 2}, nil, {3}, {4, 5, 6}, {7}}, consumer)
+	assert.Equal([]int{1, 2, 3, 4}, result)
+}
+
 func TestMaybeMap(t *testing.T) {
 	assert := assert.New(t)
 	var zeroTo10By2 []string
@@ -360,6 +457,157 @@ func TestAsFunc(t *testing.T) {
 	assert.Equal([]string{"Hello", "World"}, result)
 }
 
+func TestFinishPassedThrough(t *testing.T) {
+	assert := assert.New(t)
+	var tracker finishTracker[int]
+	consumer := consume2.Map(
+		consume2.MaybeMap(
+			consume2.Filterp(
+				consume2.Filter(
+					consume2.TakeWhile(
+						consume2.Slice[int](&tracker, 0, 3),
+						func(value int) bool { return true }),
+					func(value int) bool { return true }),
+				func(ptr *int) bool { return true }),
+			func(value int) (int, bool) { return value, true }),
+		func(value int) int { return value })
+	feedInts(consumer)
+	assert.Zero(tracker.finished)
+	consume2.Finish(consumer)
+	assert.Equal([]int{0, 1, 2}, tracker.values)
+	assert.Equal(1, tracker.finished)
+}
+
+func TestFinishCompose(t *testing.T) {
+	assert := assert.New(t)
+	var first, second finishTracker[int]
+	composite := consume2.Compose(
+		consume2.Slice[int](&first, 0, 1),
+		consume2.Slice[int](&second, 0, 2))
+	feedInts(composite)
+	consume2.Finish(composite)
+	assert.Equal([]int{0}, first.values)
+	assert.Equal([]int{0, 1}, second.values)
+	assert.Equal(1, first.finished)
+	assert.Equal(1, second.finished)
+}
+
+func TestFinishNotFinisher(t *testing.T) {
+	var result []int
+	consume2.Finish(consume2.AppendTo(&result))
+	consume2.Finish(consume2.Nil[int]())
+}
+
+func TestMapErr(t *testing.T) {
+	assert := assert.New(t)
+	errTooBig := errors.New("too big")
+	var zeroTo3 []string
+	consumer := consume2.MapErr(
+		consume2.AppendTo(&zeroTo3),
+		func(value int) (string, error) {
+			if value > 3 {
+				return "", errTooBig
+			}
+			return strconv.Itoa(value), nil
+		})
+	feedInts(consumer)
+	assert.False(consumer.CanConsume())
+	assert.Equal([]string{"0", "1", "2", "3"}, zeroTo3)
+	assert.Equal(errTooBig, consume2.Err(consumer))
+}
+
+func TestFilterErr(t *testing.T) {
+	assert := assert.New(t)
+	errTooBig := errors.New("too big")
+	var evens []int
+	consumer := consume2.FilterErr(
+		consume2.AppendTo(&evens),
+		func(value int) (bool, error) {
+			if value > 6 {
+				return false, errTooBig
+			}
+			return value%2 == 0, nil
+		})
+	feedInts(consumer)
+	assert.False(consumer.CanConsume())
+	assert.Equal([]int{0, 2, 4, 6}, evens)
+	assert.Equal(errTooBig, consume2.Err(consumer))
+}
+
+func TestErrPassedThrough(t *testing.T) {
+	assert := assert.New(t)
+	errBad := errors.New("bad")
+	var result []int
+	consumer := consume2.Filter(
+		consume2.Slice(
+			consume2.MapErr(
+				consume2.AppendTo(&result),
+				func(value int) (int, error) {
+					if value == 2 {
+						return 0, errBad
+					}
+					return value, nil
+				}),
+			0, 10),
+		func(value int) bool { return true })
+	assert.NoError(consume2.Err(consumer))
+	feedInts(consumer)
+	assert.Equal([]int{0, 1}, result)
+	assert.Equal(errBad, consume2.Err(consumer))
+}
+
+func TestErrCompose(t *testing.T) {
+	assert := assert.New(t)
+	errBad := errors.New("bad")
+	var good, bad []int
+	composite := consume2.Compose(
+		consume2.Slice(consume2.AppendTo(&good), 0, 5),
+		consume2.FilterErr(
+			consume2.AppendTo(&bad),
+			func(value int) (bool, error) {
+				if value == 1 {
+					return false, errBad
+				}
+				return true, nil
+			}))
+	feedInts(composite)
+	assert.Equal([]int{0, 1, 2, 3, 4}, good)
+	assert.Equal([]int{0}, bad)
+	assert.Equal(errBad, consume2.Err(composite))
+}
+
+func TestErrNotErrConsumer(t *testing.T) {
+	var result []int
+	assert.NoError(t, consume2.Err(consume2.AppendTo(&result)))
+}
+
+func TestWithContext(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	var result []int
+	consumer := consume2.WithContext(ctx, consume2.AppendTo(&result))
+	assert.True(consumer.CanConsume())
+	consumer.Consume(1)
+	consumer.Consume(2)
+	cancel()
+	assert.False(consumer.CanConsume())
+	consumer.Consume(3)
+	assert.Equal([]int{1, 2}, result)
+	assert.Equal(context.Canceled, consume2.Err(consumer))
+}
+
+func TestWithContextInnerFinishes(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var result []int
+	consumer := consume2.WithContext(
+		ctx, consume2.Slice(consume2.AppendTo(&result), 0, 3))
+	feedInts(consumer)
+	assert.Equal([]int{0, 1, 2}, result)
+	assert.NoError(consume2.Err(consumer))
+}
+
 func BenchmarkAppendTo(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -422,6 +670,21 @@ func BenchmarkPagerFilter(b *testing.B) {
 	}
 }
 
+type finishTracker[T any] struct {
+	values   []T
+	finished int
+}
+
+func (f *finishTracker[T]) CanConsume() bool { return true }
+
+func (f *finishTracker[T]) Consume(value T) {
+	f.values = append(f.values, value)
+}
+
+func (f *finishTracker[T]) Finish() {
+	f.finished++
+}
+
 func feedInts(consumer consume2.Consumer[int]) {
 	idx := 0
 	for consumer.CanConsume() {
@@ -0,0 +1,150 @@
+package consume2
+
+import (
+	"container/list"
+	"math"
+)
+
+// KeySet[K] remembers the keys that a PDistinct or PDistinctBy stage has
+// seen.
+type KeySet[K comparable] interface {
+
+	// Add adds key to this set and reports whether key was new. Sets that
+	// forget keys or that can report false positives may return false for
+	// a key never added.
+	Add(key K) bool
+}
+
+// DistinctMemory[K] creates the KeySet[K] for each consumer that a
+// PDistinct or PDistinctBy stage creates.
+type DistinctMemory[K comparable] func() KeySet[K]
+
+// ExactMemory[K] returns a DistinctMemory[K] that remembers every key in a
+// hash set. Its memory use grows with the number of distinct keys.
+func ExactMemory[K comparable]() DistinctMemory[K] {
+	return func() KeySet[K] {
+		return make(hashSet[K])
+	}
+}
+
+// LRUMemory[K] returns a DistinctMemory[K] that remembers only the
+// capacity most recently seen keys. A key that was forgotten counts as new
+// when seen again. LRUMemory[K] panics if capacity <= 0.
+func LRUMemory[K comparable](capacity int) DistinctMemory[K] {
+	if capacity <= 0 {
+		panic("capacity must be positive")
+	}
+	return func() KeySet[K] {
+		return &lruSet[K]{
+			capacity: capacity,
+			elements: make(map[K]*list.Element),
+			order:    list.New(),
+		}
+	}
+}
+
+// BloomMemory[K] returns a DistinctMemory[K] that remembers keys in a
+// Bloom filter sized for expected distinct keys with a false positive
+// rate of falsePositiveRate. A false positive makes a new key look like
+// one already seen. hash returns the hash of a key; equal keys must have
+// the same hash. BloomMemory[K] panics if expected <= 0 or if
+// falsePositiveRate is not strictly between 0 and 1.
+func BloomMemory[K comparable](
+	expected int,
+	falsePositiveRate float64,
+	hash func(K) uint64) DistinctMemory[K] {
+	if expected <= 0 {
+		panic("expected must be positive")
+	}
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		panic("falsePositiveRate must be between 0 and 1")
+	}
+	bitCount := math.Ceil(
+		-float64(expected) * math.Log(falsePositiveRate) /
+			(math.Ln2 * math.Ln2))
+	hashCount := int(math.Round(bitCount / float64(expected) * math.Ln2))
+	if hashCount < 1 {
+		hashCount = 1
+	}
+	return func() KeySet[K] {
+		return &bloomSet[K]{
+			bits:      make([]uint64, (uint64(bitCount)+63)/64),
+			bitCount:  uint64(bitCount),
+			hashCount: hashCount,
+			hash:      hash,
+		}
+	}
+}
+
+// PDistinct returns a Pipeline that emits only the T values it has not
+// seen before. memory controls how the pipeline remembers the T values
+// it has seen.
+func PDistinct[T comparable](memory DistinctMemory[T]) Pipeline[T, T] {
+	return PDistinctBy(func(value T) T { return value }, memory)
+}
+
+// PDistinctBy returns a Pipeline that emits only the T values whose key
+// it has not seen before. key returns the key of a T value. memory
+// controls how the pipeline remembers the keys it has seen.
+func PDistinctBy[T any, K comparable](
+	key func(T) K, memory DistinctMemory[K]) Pipeline[T, T] {
+	return func(inner Consumer[T]) Consumer[T] {
+		seen := memory()
+		return Filter(inner, func(value T) bool {
+			return seen.Add(key(value))
+		})
+	}
+}
+
+type hashSet[K comparable] map[K]struct{}
+
+func (h hashSet[K]) Add(key K) bool {
+	if _, ok := h[key]; ok {
+		return false
+	}
+	h[key] = struct{}{}
+	return true
+}
+
+type lruSet[K comparable] struct {
+	capacity int
+	elements map[K]*list.Element
+	order    *list.List
+}
+
+func (l *lruSet[K]) Add(key K) bool {
+	if element, ok := l.elements[key]; ok {
+		l.order.MoveToFront(element)
+		return false
+	}
+	l.elements[key] = l.order.PushFront(key)
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.elements, oldest.Value.(K))
+	}
+	return true
+}
+
+type bloomSet[K comparable] struct {
+	bits      []uint64
+	bitCount  uint64
+	hashCount int
+	hash      func(K) uint64
+}
+
+func (b *bloomSet[K]) Add(key K) bool {
+	// Double hashing derives all the bit positions from two hashes.
+	h1 := mix64(b.hash(key))
+	h2 := mix64(h1^0x9e3779b97f4a7c15) | 1
+	added := false
+	for i := 0; i < b.hashCount; i++ {
+		bit := (h1 + uint64(i)*h2) % b.bitCount
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			b.bits[word] |= mask
+			added = true
+		}
+	}
+	return added
+}
@@ -0,0 +1,61 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPDistinctExact(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.PDistinct(consume2.ExactMemory[int]())
+	var result []int
+	consume2.FromSlice(
+		[]int{3, 1, 3, 2, 1, 4, 2}, pipeline.AppendTo(&result))
+	assert.Equal([]int{3, 1, 2, 4}, result)
+
+	// Each consumer the pipeline creates starts with empty memory.
+	result = nil
+	consume2.FromSlice([]int{1, 1, 5}, pipeline.AppendTo(&result))
+	assert.Equal([]int{1, 5}, result)
+}
+
+func TestPDistinctBy(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.Join(
+		consume2.PDistinctBy(
+			func(p person) bool { return p.Age >= 40 },
+			consume2.ExactMemory[bool]()),
+		consume2.PMap(func(p person) string { return p.Name }))
+	var result []string
+	consume2.FromSlice(people, pipeline.AppendTo(&result))
+	assert.Equal([]string{"Mark", "Dillon"}, result)
+}
+
+func TestPDistinctLRU(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.PDistinct(consume2.LRUMemory[int](2))
+	var result []int
+	consume2.FromSlice(
+		[]int{1, 2, 1, 3, 1, 2, 2, 3}, pipeline.AppendTo(&result))
+	assert.Equal([]int{1, 2, 3, 2, 3}, result)
+}
+
+func TestPDistinctBloom(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.PDistinct(
+		consume2.BloomMemory(10000, 0.01, intHash))
+	var counter consume2.Counter[int]
+	consume2.FromSlice(repeatInts(10000, 3), pipeline.Run(&counter))
+	assert.LessOrEqual(counter.Result(), 10000)
+	assert.Greater(counter.Result(), 9700)
+}
+
+func TestDistinctMemoryPanics(t *testing.T) {
+	assert := assert.New(t)
+	assert.Panics(func() { consume2.LRUMemory[int](0) })
+	assert.Panics(func() { consume2.BloomMemory(0, 0.01, intHash) })
+	assert.Panics(func() { consume2.BloomMemory(10, 0, intHash) })
+	assert.Panics(func() { consume2.BloomMemory(10, 1, intHash) })
+}
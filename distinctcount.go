@@ -0,0 +1,138 @@
+package consume2
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+)
+
+const distinctCountVersion = 1
+
+// DistinctCount[T] is a Consumer[T] that estimates how many distinct T
+// values it consumes using HyperLogLog. Its memory use depends only on its
+// precision, not on the number of values consumed. DistinctCount[T]
+// implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler.
+type DistinctCount[T any] struct {
+	hash      func(T) uint64
+	precision int
+	registers []uint8
+}
+
+// NewDistinctCount[T] creates a DistinctCount[T]. precision is between 4
+// and 18 inclusive. Higher precision gives more accurate estimates at the
+// cost of 2^precision bytes of memory. At precision 14, the typical error
+// is under 1%. hash returns the hash of a T value; equal T values must
+// have the same hash. hash need not mix its bits well since
+// DistinctCount[T] mixes them again. NewDistinctCount[T] panics if
+// precision is out of range.
+func NewDistinctCount[T any](
+	precision int, hash func(T) uint64) *DistinctCount[T] {
+	if precision < 4 || precision > 18 {
+		panic("precision must be between 4 and 18")
+	}
+	return &DistinctCount[T]{
+		hash:      hash,
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+// CanConsume always returns true.
+func (d *DistinctCount[T]) CanConsume() bool {
+	return true
+}
+
+// Consume includes value in the estimate.
+func (d *DistinctCount[T]) Consume(value T) {
+	x := mix64(d.hash(value))
+	index := x >> (64 - d.precision)
+	rank := uint8(bits.LeadingZeros64(x<<d.precision|1<<(d.precision-1)) + 1)
+	if rank > d.registers[index] {
+		d.registers[index] = rank
+	}
+}
+
+// Result returns the estimated number of distinct T values consumed.
+func (d *DistinctCount[T]) Result() uint64 {
+	m := float64(len(d.registers))
+	var sum float64
+	var zeros int
+	for _, r := range d.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := hllAlpha(len(d.registers)) * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// Merge combines the estimate in other into this instance as if this
+// instance had also consumed the T values that other consumed. Merge
+// returns an error if other has a different precision.
+func (d *DistinctCount[T]) Merge(other *DistinctCount[T]) error {
+	if d.precision != other.precision {
+		return errors.New("consume2: DistinctCount precisions differ")
+	}
+	for i, r := range other.registers {
+		if r > d.registers[i] {
+			d.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes this instance. The encoding does not include the
+// hash function.
+func (d *DistinctCount[T]) MarshalBinary() ([]byte, error) {
+	result := make([]byte, 0, 2+len(d.registers))
+	result = append(result, distinctCountVersion, uint8(d.precision))
+	return append(result, d.registers...), nil
+}
+
+// UnmarshalBinary decodes data that MarshalBinary encoded and stores it
+// in this instance. UnmarshalBinary keeps the hash function of this
+// instance, so this instance should come from NewDistinctCount[T] with the
+// same hash function as the encoded instance.
+func (d *DistinctCount[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] != distinctCountVersion {
+		return errBadDistinctCount
+	}
+	precision := int(data[1])
+	if precision < 4 || precision > 18 || len(data)-2 != 1<<precision {
+		return errBadDistinctCount
+	}
+	d.precision = precision
+	d.registers = make([]uint8, 1<<precision)
+	copy(d.registers, data[2:])
+	return nil
+}
+
+var errBadDistinctCount = errors.New("consume2: malformed DistinctCount data")
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/float64(m))
+	}
+}
+
+// mix64 is the finalizer of splitmix64. It spreads the bits of a hash
+// that may be poorly distributed such as the identity hash of an int.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
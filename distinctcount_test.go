@@ -0,0 +1,77 @@
+package consume2_test
+
+import (
+	"hash/fnv"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinctCount(t *testing.T) {
+	assert := assert.New(t)
+	distinct := consume2.NewDistinctCount(14, intHash)
+	var counter consume2.Counter[int]
+	consume2.FromSlice(
+		repeatInts(100000, 3), consume2.Compose[int](distinct, &counter))
+	assert.Equal(300000, counter.Result())
+	assert.InEpsilon(100000, distinct.Result(), 0.03)
+}
+
+func TestDistinctCountSmall(t *testing.T) {
+	assert := assert.New(t)
+	distinct := consume2.NewDistinctCount(10, stringHash)
+	assert.Zero(distinct.Result())
+	consume2.FromSlice[string](
+		[]string{"a", "b", "a", "c", "b", "a"}, distinct)
+	assert.Equal(uint64(3), distinct.Result())
+}
+
+func TestDistinctCountMerge(t *testing.T) {
+	assert := assert.New(t)
+	first := consume2.NewDistinctCount(12, intHash)
+	second := consume2.NewDistinctCount(12, intHash)
+	consume2.FromSlice[int](repeatInts(30000, 1), first)
+	consume2.FromSlice[int](repeatInts(50000, 1), second)
+	assert.NoError(first.Merge(second))
+	assert.InEpsilon(50000, first.Result(), 0.05)
+	assert.Error(first.Merge(consume2.NewDistinctCount(10, intHash)))
+}
+
+func TestDistinctCountMarshal(t *testing.T) {
+	assert := assert.New(t)
+	distinct := consume2.NewDistinctCount(8, intHash)
+	consume2.FromSlice[int](repeatInts(1000, 2), distinct)
+	data, err := distinct.MarshalBinary()
+	assert.NoError(err)
+	decoded := consume2.NewDistinctCount(4, intHash)
+	assert.NoError(decoded.UnmarshalBinary(data))
+	assert.Equal(distinct.Result(), decoded.Result())
+	assert.NoError(decoded.Merge(distinct))
+	assert.Error(decoded.UnmarshalBinary(data[:len(data)-1]))
+}
+
+func TestNewDistinctCountPanics(t *testing.T) {
+	assert.Panics(t, func() { consume2.NewDistinctCount(3, intHash) })
+	assert.Panics(t, func() { consume2.NewDistinctCount(19, intHash) })
+}
+
+func intHash(x int) uint64 {
+	return uint64(x)
+}
+
+func stringHash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+func repeatInts(n, times int) []int {
+	result := make([]int, 0, n*times)
+	for i := 0; i < times; i++ {
+		for j := 0; j < n; j++ {
+			result = append(result, j)
+		}
+	}
+	return result
+}
@@ -1,26 +1,39 @@
 package consume2
 
-// FromSlice sends values in aslice to consumer.
-func FromSlice[T any](aslice []T, consumer Consumer[T]) {
+import (
+	"context"
+)
+
+// FromSlice sends values in aslice to consumer. FromSlice calls Finish on
+// consumer after sending the last value and returns the error consumer
+// encountered, if any.
+func FromSlice[T any](aslice []T, consumer Consumer[T]) error {
 	for index := 0; index < len(aslice) && consumer.CanConsume(); index++ {
 		consumer.Consume(aslice[index])
 	}
+	Finish(consumer)
+	return Err(consumer)
 }
 
 // FromPtrSlice sends values in aslice to consumer skipping nil pointers in
-// aslice.
-func FromPtrSlice[T any](aslice []*T, consumer Consumer[T]) {
+// aslice. FromPtrSlice calls Finish on consumer after sending the last
+// value and returns the error consumer encountered, if any.
+func FromPtrSlice[T any](aslice []*T, consumer Consumer[T]) error {
 	for index := 0; index < len(aslice) && consumer.CanConsume(); index++ {
 		if aslice[index] == nil {
 			continue
 		}
 		consumer.Consume(*aslice[index])
 	}
+	Finish(consumer)
+	return Err(consumer)
 }
 
 // FromIntGenerator sends ints from generator to consumer. generator returns
-// a negative number when there are no more ints to send.
-func FromIntGenerator(generator func() int, consumer Consumer[int]) {
+// a negative number when there are no more ints to send. FromIntGenerator
+// calls Finish on consumer after sending the last int and returns the error
+// consumer encountered, if any.
+func FromIntGenerator(generator func() int, consumer Consumer[int]) error {
 	for consumer.CanConsume() {
 		value := generator()
 		if value < 0 {
@@ -28,11 +41,16 @@ func FromIntGenerator(generator func() int, consumer Consumer[int]) {
 		}
 		consumer.Consume(value)
 	}
+	Finish(consumer)
+	return Err(consumer)
 }
 
 // FromGenerator sends values from generator to consumer. generator returns
-// false when there are no more values to send.
-func FromGenerator[T any](generator func() (T, bool), consumer Consumer[T]) {
+// false when there are no more values to send. FromGenerator calls Finish
+// on consumer after sending the last value and returns the error consumer
+// encountered, if any.
+func FromGenerator[T any](
+	generator func() (T, bool), consumer Consumer[T]) error {
 	for consumer.CanConsume() {
 		value, ok := generator()
 		if !ok {
@@ -40,4 +58,23 @@ func FromGenerator[T any](generator func() (T, bool), consumer Consumer[T]) {
 		}
 		consumer.Consume(value)
 	}
+	Finish(consumer)
+	return Err(consumer)
+}
+
+// FromSliceContext works like FromSlice except that it stops sending values
+// once ctx is done. In that case, FromSliceContext returns ctx.Err().
+func FromSliceContext[T any](
+	ctx context.Context, aslice []T, consumer Consumer[T]) error {
+	return FromSlice(aslice, WithContext(ctx, consumer))
+}
+
+// FromGeneratorContext works like FromGenerator except that it stops
+// sending values once ctx is done. In that case, FromGeneratorContext
+// returns ctx.Err().
+func FromGeneratorContext[T any](
+	ctx context.Context,
+	generator func() (T, bool),
+	consumer Consumer[T]) error {
+	return FromGenerator(generator, WithContext(ctx, consumer))
 }
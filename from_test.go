@@ -1,7 +1,9 @@
 package consume2_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/keep94/consume2"
 	"github.com/stretchr/testify/assert"
@@ -52,6 +54,71 @@ func TestFromGenerator(t *testing.T) {
 	assert.Equal(t, []int{1, 8, 27, 64, 125}, cubes)
 }
 
+func TestFromFinishes(t *testing.T) {
+	assert := assert.New(t)
+	var fromSlice, fromPtrSlice, fromInts, fromGenerator finishTracker[int]
+	consume2.FromSlice([]int{1, 2, 3}, consume2.Slice[int](&fromSlice, 0, 2))
+	one, two := 1, 2
+	consume2.FromPtrSlice[int]([]*int{&one, nil, &two}, &fromPtrSlice)
+	consume2.FromIntGenerator(squaresLessThan36(), &fromInts)
+	consume2.FromGenerator[int](cubesLessThan216(), &fromGenerator)
+	assert.Equal([]int{1, 2}, fromSlice.values)
+	assert.Equal(1, fromSlice.finished)
+	assert.Equal([]int{1, 2}, fromPtrSlice.values)
+	assert.Equal(1, fromPtrSlice.finished)
+	assert.Equal([]int{1, 4, 9, 16, 25}, fromInts.values)
+	assert.Equal(1, fromInts.finished)
+	assert.Equal([]int{1, 8, 27, 64, 125}, fromGenerator.values)
+	assert.Equal(1, fromGenerator.finished)
+}
+
+func TestFromSliceContext(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	var result []int
+	consumer := consume2.Call(func(value int) {
+		result = append(result, value)
+		if value == 2 {
+			cancel()
+		}
+	})
+	err := consume2.FromSliceContext(ctx, []int{1, 2, 3, 4}, consumer)
+	assert.Equal(context.Canceled, err)
+	assert.Equal([]int{1, 2}, result)
+}
+
+func TestFromSliceContextNotCancelled(t *testing.T) {
+	var result []int
+	err := consume2.FromSliceContext(
+		context.Background(), []int{1, 2, 3}, consume2.AppendTo(&result))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, result)
+}
+
+func TestFromGeneratorContext(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 10*time.Millisecond)
+	defer cancel()
+	index := 0
+	generator := func() (int, bool) {
+		index++
+		return index, true
+	}
+	var count int
+	err := consume2.FromGeneratorContext(
+		ctx,
+		generator,
+		consume2.Call(func(value int) {
+			count++
+			if count == 3 {
+				<-ctx.Done()
+			}
+		}))
+	assert.Equal(context.DeadlineExceeded, err)
+	assert.Equal(3, count)
+}
+
 func squaresLessThan36() func() int {
 	index := 1
 	return func() int {
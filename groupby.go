@@ -0,0 +1,84 @@
+package consume2
+
+// GroupBy[T,K,C] is a Consumer[T] that sends each T value it consumes to a
+// consumer of type C for that value's key. GroupBy[T,K,C] creates the
+// consumer for a key the first time it sees that key. This way, a single
+// pass over the T values can fill in a PageBuilder, a Counter, or any
+// other consumer per key.
+type GroupBy[T any, K comparable, C Consumer[T]] struct {
+	key         func(T) K
+	newConsumer func(K) C
+	groups      map[K]C
+	keys        []K
+}
+
+// NewGroupBy[T,K,C] creates a GroupBy[T,K,C]. key returns the key of a T
+// value. newConsumer creates the consumer for a key.
+func NewGroupBy[T any, K comparable, C Consumer[T]](
+	key func(T) K, newConsumer func(K) C) *GroupBy[T, K, C] {
+	return &GroupBy[T, K, C]{
+		key:         key,
+		newConsumer: newConsumer,
+		groups:      make(map[K]C),
+	}
+}
+
+// CanConsume always returns true as new keys may appear at any time.
+func (g *GroupBy[T, K, C]) CanConsume() bool {
+	return true
+}
+
+// Consume sends value to the consumer for its key. If that consumer can
+// consume no more values, value is dropped.
+func (g *GroupBy[T, K, C]) Consume(value T) {
+	key := g.key(value)
+	consumer, ok := g.groups[key]
+	if !ok {
+		consumer = g.newConsumer(key)
+		g.groups[key] = consumer
+		g.keys = append(g.keys, key)
+	}
+	if consumer.CanConsume() {
+		consumer.Consume(value)
+	}
+}
+
+// Finish calls Finish on the consumer for each key.
+func (g *GroupBy[T, K, C]) Finish() {
+	for _, key := range g.keys {
+		Finish[T](g.groups[key])
+	}
+}
+
+// Err returns the first error found among the consumers for each key
+// going in the order that the keys first appeared.
+func (g *GroupBy[T, K, C]) Err() error {
+	for _, key := range g.keys {
+		if err := Err[T](g.groups[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys seen so far in the order they first appeared.
+func (g *GroupBy[T, K, C]) Keys() []K {
+	result := make([]K, len(g.keys))
+	copy(result, g.keys)
+	return result
+}
+
+// Get returns the consumer for key. ok is false if key has not been seen.
+func (g *GroupBy[T, K, C]) Get(key K) (consumer C, ok bool) {
+	consumer, ok = g.groups[key]
+	return
+}
+
+// Groups returns the consumer for each key seen so far.
+func (g *GroupBy[T, K, C]) Groups() map[K]C {
+	result := make(map[K]C, len(g.groups))
+	for key, consumer := range g.groups {
+		result[key] = consumer
+	}
+	return result
+}
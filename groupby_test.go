@@ -0,0 +1,73 @@
+package consume2_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupBy(t *testing.T) {
+	assert := assert.New(t)
+	groupBy := consume2.NewGroupBy(
+		func(x int) int { return x % 3 },
+		func(key int) *consume2.PageBuilder[int] {
+			return consume2.NewPageBuilder[int](0, 2)
+		})
+	consume2.FromSlice[int]([]int{5, 3, 4, 6, 7, 9, 8, 12}, groupBy)
+	assert.Equal([]int{2, 0, 1}, groupBy.Keys())
+	pager, ok := groupBy.Get(0)
+	assert.True(ok)
+	values, morePages := pager.Build()
+	assert.Equal([]int{3, 6}, values)
+	assert.True(morePages)
+	pager, _ = groupBy.Get(2)
+	values, morePages = pager.Build()
+	assert.Equal([]int{5, 8}, values)
+	assert.False(morePages)
+	_, ok = groupBy.Get(3)
+	assert.False(ok)
+	assert.Len(groupBy.Groups(), 3)
+}
+
+func TestGroupByCounters(t *testing.T) {
+	assert := assert.New(t)
+	groupBy := consume2.NewGroupBy(
+		func(p person) bool { return p.Age >= 40 },
+		func(key bool) *consume2.Counter[person] {
+			return &consume2.Counter[person]{}
+		})
+	consume2.FromSlice[person](people, groupBy)
+	counts := make(map[bool]int)
+	for key, counter := range groupBy.Groups() {
+		counts[key] = counter.Result()
+	}
+	assert.Equal(map[bool]int{true: 4, false: 1}, counts)
+}
+
+func TestGroupByFinishAndErr(t *testing.T) {
+	assert := assert.New(t)
+	errOdd := errors.New("odd")
+	trackers := make(map[int]*finishTracker[int])
+	groupBy := consume2.NewGroupBy(
+		func(x int) int { return x % 2 },
+		func(key int) consume2.Consumer[int] {
+			tracker := &finishTracker[int]{}
+			trackers[key] = tracker
+			return consume2.MapErr[int, int](
+				tracker,
+				func(x int) (int, error) {
+					if x == 5 {
+						return 0, errOdd
+					}
+					return x, nil
+				})
+		})
+	err := consume2.FromSlice[int]([]int{1, 2, 3, 4, 5, 6, 7}, groupBy)
+	assert.Equal(errOdd, err)
+	assert.Equal([]int{1, 3}, trackers[1].values)
+	assert.Equal([]int{2, 4, 6}, trackers[0].values)
+	assert.Equal(1, trackers[0].finished)
+	assert.Equal(1, trackers[1].finished)
+}
@@ -0,0 +1,72 @@
+package consume2
+
+// heap is a binary heap of T values. The first value is always the
+// smallest according to less.
+type heap[T any] struct {
+	values []T
+	less   func(a, b T) bool
+}
+
+func (h *heap[T]) Len() int {
+	return len(h.values)
+}
+
+// First returns the smallest value.
+func (h *heap[T]) First() T {
+	return h.values[0]
+}
+
+func (h *heap[T]) Push(value T) {
+	h.values = append(h.values, value)
+	h.up(len(h.values) - 1)
+}
+
+// Pop removes and returns the smallest value.
+func (h *heap[T]) Pop() T {
+	result := h.values[0]
+	last := len(h.values) - 1
+	h.values[0] = h.values[last]
+	var zero T
+	h.values[last] = zero
+	h.values = h.values[:last]
+	if last > 0 {
+		h.down(0)
+	}
+	return result
+}
+
+// ReplaceFirst replaces the smallest value with value.
+func (h *heap[T]) ReplaceFirst(value T) {
+	h.values[0] = value
+	h.down(0)
+}
+
+func (h *heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.values[i], h.values[parent]) {
+			break
+		}
+		h.values[i], h.values[parent] = h.values[parent], h.values[i]
+		i = parent
+	}
+}
+
+func (h *heap[T]) down(i int) {
+	n := len(h.values)
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && h.less(h.values[left], h.values[smallest]) {
+			smallest = left
+		}
+		if right < n && h.less(h.values[right], h.values[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h.values[i], h.values[smallest] = h.values[smallest], h.values[i]
+		i = smallest
+	}
+}
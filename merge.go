@@ -0,0 +1,68 @@
+package consume2
+
+// MergeSorted returns a Source that merges the T values from generators
+// into a single sorted sequence. Each generator returns its T values in
+// sorted order according to less and returns false when it has no more
+// T values. Among equal T values, those from earlier generators come
+// first. The returned source pulls a T value from a generator only when
+// it needs that value, so it stops pulling as soon as its consumer can
+// consume no more values. The returned source can be run only once.
+func MergeSorted[T any](
+	less func(a, b T) bool, generators ...func() (T, bool)) Source[T] {
+	generatorList := make([]func() (T, bool), len(generators))
+	copy(generatorList, generators)
+	return GeneratorSource(mergeGenerators(less, generatorList))
+}
+
+// mergeGenerators returns a generator that merges the sorted values of
+// generators into a single sorted sequence. Among equal values, those
+// from earlier generators come first.
+func mergeGenerators[T any](
+	less func(a, b T) bool,
+	generators []func() (T, bool)) func() (T, bool) {
+	h := heap[mergeEntry[T]]{
+		less: func(a, b mergeEntry[T]) bool {
+			if less(a.value, b.value) {
+				return true
+			}
+			if less(b.value, a.value) {
+				return false
+			}
+			return a.index < b.index
+		},
+	}
+	started := false
+	pending := -1
+	return func() (value T, ok bool) {
+		if !started {
+			started = true
+			for i, generator := range generators {
+				if value, ok := generator(); ok {
+					h.Push(mergeEntry[T]{value: value, index: i})
+				}
+			}
+		}
+
+		// Replace the value returned last time only now so that we don't
+		// pull a value that no one asks for.
+		if pending >= 0 {
+			if next, ok := generators[pending](); ok {
+				h.ReplaceFirst(mergeEntry[T]{value: next, index: pending})
+			} else {
+				h.Pop()
+			}
+			pending = -1
+		}
+		if h.Len() == 0 {
+			return
+		}
+		first := h.First()
+		pending = first.index
+		return first.value, true
+	}
+}
+
+type mergeEntry[T any] struct {
+	value T
+	index int
+}
@@ -0,0 +1,72 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSorted(t *testing.T) {
+	assert := assert.New(t)
+	values, err := consume2.MergeSorted(
+		intLess,
+		sliceGenerator([]int{1, 4, 7, 10}),
+		sliceGenerator([]int{2, 4, 6}),
+		sliceGenerator[int](nil),
+		sliceGenerator([]int{0, 11})).Collect()
+	assert.NoError(err)
+	assert.Equal([]int{0, 1, 2, 4, 4, 6, 7, 10, 11}, values)
+}
+
+func TestMergeSortedStable(t *testing.T) {
+	assert := assert.New(t)
+	byAge := func(a, b person) bool { return a.Age < b.Age }
+	values, err := consume2.MergeSorted(
+		byAge,
+		sliceGenerator([]person{{Name: "a", Age: 1}, {Name: "b", Age: 2}}),
+		sliceGenerator([]person{{Name: "c", Age: 1}, {Name: "d", Age: 2}}),
+	).Collect()
+	assert.NoError(err)
+	assert.Equal(
+		[]person{
+			{Name: "a", Age: 1},
+			{Name: "c", Age: 1},
+			{Name: "b", Age: 2},
+			{Name: "d", Age: 2},
+		},
+		values)
+}
+
+func TestMergeSortedStopsEarly(t *testing.T) {
+	assert := assert.New(t)
+	var pulls int
+	counting := func(g func() (int, bool)) func() (int, bool) {
+		return func() (int, bool) {
+			pulls++
+			return g()
+		}
+	}
+	values, err := consume2.MergeSorted(
+		intLess,
+		counting(cubesLessThan216()),
+		counting(sliceGenerator([]int{2, 3, 50, 60}))).Take(3).Collect()
+	assert.NoError(err)
+	assert.Equal([]int{1, 2, 3}, values)
+	assert.Equal(4, pulls)
+	_, err = consume2.MergeSorted(
+		intLess, counting(cubesLessThan216())).Take(0).Collect()
+	assert.NoError(err)
+	assert.Equal(4, pulls)
+}
+
+func sliceGenerator[T any](values []T) func() (T, bool) {
+	return func() (value T, ok bool) {
+		if len(values) == 0 {
+			return
+		}
+		value = values[0]
+		values = values[1:]
+		return value, true
+	}
+}
@@ -70,6 +70,60 @@ func PMaybeMap[T, U any](mapper func(T) (U, bool)) Pipeline[T, U] {
 	}
 }
 
+// PScan returns a Pipeline that emits a running fold of the T values it
+// receives. For each T value, the pipeline computes step(acc, value),
+// emits the result, and uses it as acc for the next T value. acc starts
+// out as init.
+func PScan[T, A any](init A, step func(A, T) A) Pipeline[T, A] {
+	return func(inner Consumer[A]) Consumer[T] {
+		return Scan(inner, init, step)
+	}
+}
+
+// PFlatMap returns a Pipeline that applies mapper to the T values it
+// receives and emits each of the resulting U values.
+func PFlatMap[T, U any](mapper func(T) []U) Pipeline[T, U] {
+	return func(inner Consumer[U]) Consumer[T] {
+		return FlatMap(inner, mapper)
+	}
+}
+
+// PExpand returns a Pipeline that calls emit on the T values it receives
+// and emits the U values that emit sends to the consumer passed to it.
+// See Expand.
+func PExpand[T, U any](
+	emit func(value T, consumer Consumer[U])) Pipeline[T, U] {
+	return func(inner Consumer[U]) Consumer[T] {
+		return Expand(inner, emit)
+	}
+}
+
+// PFlatten returns a Pipeline that emits each T value in the slices it
+// receives.
+func PFlatten[T any]() Pipeline[[]T, T] {
+	return func(inner Consumer[T]) Consumer[[]T] {
+		return Flatten(inner)
+	}
+}
+
+// PMapErr works like PMap except that mapper can fail. The first error
+// mapper returns stops the returned pipeline, and Err reports that error
+// for the pipeline's consumer.
+func PMapErr[T, U any](mapper func(T) (U, error)) Pipeline[T, U] {
+	return func(inner Consumer[U]) Consumer[T] {
+		return MapErr(inner, mapper)
+	}
+}
+
+// PFilterErr works like PFilter except that filter can fail. The first
+// error filter returns stops the returned pipeline, and Err reports that
+// error for the pipeline's consumer.
+func PFilterErr[T any](filter func(value T) (bool, error)) Pipeline[T, T] {
+	return func(inner Consumer[T]) Consumer[T] {
+		return FilterErr(inner, filter)
+	}
+}
+
 // PSlice returns a Pipeline that emits the start T value it receives
 // inclusive up to the end T value it receives exclusive. start and end are
 // zero based.
@@ -87,6 +141,15 @@ func PTakeWhile[T any](filter func(value T) bool) Pipeline[T, T] {
 	}
 }
 
+// PTee returns a Pipeline that emits the same T values it receives while
+// also sending them to side. side only observes the T values; when side
+// can consume no more values, the returned pipeline keeps going. See Tee.
+func PTee[T any](side Consumer[T]) Pipeline[T, T] {
+	return func(inner Consumer[T]) Consumer[T] {
+		return Tee(inner, side)
+	}
+}
+
 // Identity returns a Pipeline that emits the same T values it receives.
 func Identity[T any]() Pipeline[T, T] {
 	return func(inner Consumer[T]) Consumer[T] {
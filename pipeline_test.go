@@ -1,6 +1,7 @@
 package consume2_test
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 	"testing"
@@ -66,6 +67,104 @@ func TestPipeline2(t *testing.T) {
 	assert.Equal(t, stringArr{"A", "C", "E"}, answer2)
 }
 
+func TestPipelineErr(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.Join(
+		consume2.PFilterErr(func(s string) (bool, error) {
+			if s == "" {
+				return false, errors.New("empty")
+			}
+			return s != "skip", nil
+		}),
+		consume2.PMapErr(strconv.Atoi))
+	var result []int
+	err := consume2.FromSlice(
+		[]string{"1", "skip", "2", "x", "3"}, pipeline.AppendTo(&result))
+	assert.Error(err)
+	assert.Equal([]int{1, 2}, result)
+	result = nil
+	err = consume2.FromSlice(
+		[]string{"1", "", "2"}, pipeline.AppendTo(&result))
+	assert.EqualError(err, "empty")
+	assert.Equal([]int{1}, result)
+	result = nil
+	err = consume2.FromSlice(
+		[]string{"1", "skip", "2"}, pipeline.AppendTo(&result))
+	assert.NoError(err)
+	assert.Equal([]int{1, 2}, result)
+}
+
+func TestPipelineTee(t *testing.T) {
+	assert := assert.New(t)
+	var evens consume2.Counter[int]
+	pipeline := consume2.Join(
+		consume2.PFilter(func(x int) bool { return x%2 == 0 }),
+		consume2.PTee[int](&evens))
+	pipelineStr := consume2.Join(pipeline, consume2.PMap(strconv.Itoa))
+	var result []string
+	consume2.FromSlice(
+		[]int{1, 2, 3, 4, 6}, pipelineStr.AppendTo(&result))
+	assert.Equal([]string{"2", "4", "6"}, result)
+	assert.Equal(3, evens.Result())
+}
+
+func TestPipelineFlatMap(t *testing.T) {
+	assert := assert.New(t)
+	type order struct {
+		ID    int
+		Items []string
+	}
+	orders := []order{
+		{ID: 1, Items: []string{"apple", "pear"}},
+		{ID: 2},
+		{ID: 3, Items: []string{"plum"}},
+	}
+	lineItems := consume2.Join(
+		consume2.PFlatMap(func(o order) []string { return o.Items }),
+		consume2.PMap(strings.ToUpper))
+	var result []string
+	consume2.FromSlice(orders, lineItems.AppendTo(&result))
+	assert.Equal([]string{"APPLE", "PEAR", "PLUM"}, result)
+
+	ids := consume2.PExpand(func(o order, inner consume2.Consumer[int]) {
+		for range o.Items {
+			inner.Consume(o.ID)
+		}
+	})
+	var idResult []int
+	consume2.FromSlice(orders, ids.AppendTo(&idResult))
+	assert.Equal([]int{1, 1, 3}, idResult)
+
+	flatten := consume2.Join(
+		consume2.PFlatten[int](), consume2.PSlice[int](0, 3))
+	idResult = nil
+	consume2.FromSlice(
+		[][]int{{1}, {2, 3, 4}, {5}}, flatten.AppendTo(&idResult))
+	assert.Equal([]int{1, 2, 3}, idResult)
+}
+
+func TestPipelineScan(t *testing.T) {
+	assert := assert.New(t)
+	balance := consume2.PScan(
+		100, func(acc int, amount int) int { return acc + amount })
+	var balances []int
+	consume2.FromSlice(
+		[]int{-20, 50, -100, 5}, balance.AppendTo(&balances))
+	assert.Equal([]int{80, 130, 30, 35}, balances)
+	runningMax := consume2.Join(
+		consume2.PScan(0, func(acc int, x int) int {
+			if x > acc {
+				return x
+			}
+			return acc
+		}),
+		consume2.PSlice[int](0, 5))
+	var maxes []int
+	consume2.FromSlice(
+		[]int{3, 1, 4, 1, 5, 9, 2}, runningMax.AppendTo(&maxes))
+	assert.Equal([]int{3, 3, 4, 4, 5}, maxes)
+}
+
 type stringArr []string
 
 func (s *stringArr) Append(x string) {
@@ -0,0 +1,93 @@
+package consume2
+
+// Puller[T,U] turns a pipeline into a pull style iterator. A Puller[T,U]
+// pulls T values from a generator, sends them through a Pipeline[T,U],
+// and hands out the U values the pipeline emits one at a time.
+type Puller[T, U any] struct {
+	generator func() (T, bool)
+	consumer  Consumer[T]
+	buffer    []U
+	head      int
+	done      bool
+	stopped   bool
+}
+
+// NewPuller[T,U] creates a Puller[T,U] that sends the T values from
+// generator through pipeline. generator returns false when there are no
+// more T values just as with FromGenerator.
+func NewPuller[T, U any](
+	generator func() (T, bool), pipeline Pipeline[T, U]) *Puller[T, U] {
+	result := &Puller[T, U]{generator: generator}
+	result.consumer = pipeline(pullerSink[T, U]{result})
+	return result
+}
+
+// Next returns the next U value. Next returns false when there are no more
+// U values. Next pulls only as many T values from the generator as needed
+// to produce the next U value. p.Next can be passed to FromGenerator.
+func (p *Puller[T, U]) Next() (value U, ok bool) {
+	for p.head == len(p.buffer) {
+		if p.done {
+			return
+		}
+		p.buffer = p.buffer[:0]
+		p.head = 0
+		p.pull()
+	}
+	value = p.buffer[p.head]
+	var zero U
+	p.buffer[p.head] = zero
+	p.head++
+	return value, true
+}
+
+// Stop releases the U values this instance has buffered. If the pipeline
+// has not finished yet, Stop finishes it so that its stages can release
+// resources such as temporary files; the pipeline sees that it can no
+// longer emit U values, and any it emits anyway are dropped. After Stop,
+// Next always returns false. Callers that stop pulling U values before
+// Next returns false should call Stop.
+func (p *Puller[T, U]) Stop() {
+	finished := p.done
+	p.done = true
+	p.stopped = true
+	p.generator = nil
+	p.buffer = nil
+	p.head = 0
+	if !finished {
+		Finish(p.consumer)
+	}
+}
+
+// Err returns the error that the pipeline encountered, if any.
+func (p *Puller[T, U]) Err() error {
+	return Err(p.consumer)
+}
+
+func (p *Puller[T, U]) pull() {
+	if p.consumer.CanConsume() {
+		if value, ok := p.generator(); ok {
+			p.consumer.Consume(value)
+			return
+		}
+	}
+	p.done = true
+	p.generator = nil
+	Finish(p.consumer)
+}
+
+// pullerSink[T,U] appends the U values a pipeline emits to the buffer of
+// a Puller[T,U] until the Puller[T,U] stops.
+type pullerSink[T, U any] struct {
+	puller *Puller[T, U]
+}
+
+func (s pullerSink[T, U]) CanConsume() bool {
+	return !s.puller.stopped
+}
+
+func (s pullerSink[T, U]) Consume(value U) {
+	if !s.puller.stopped {
+		s.puller.buffer = append(s.puller.buffer, value)
+	}
+}
@@ -0,0 +1,115 @@
+package consume2_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPuller(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.Join(
+		consume2.PFilter(func(x int) bool { return x%2 == 0 }),
+		consume2.PMap(strconv.Itoa))
+	puller := consume2.NewPuller(cubesLessThan216(), pipeline)
+	value, ok := puller.Next()
+	assert.True(ok)
+	assert.Equal("8", value)
+	value, ok = puller.Next()
+	assert.True(ok)
+	assert.Equal("64", value)
+	_, ok = puller.Next()
+	assert.False(ok)
+	_, ok = puller.Next()
+	assert.False(ok)
+	assert.NoError(puller.Err())
+}
+
+func TestPullerManyPerValue(t *testing.T) {
+	assert := assert.New(t)
+	repeat := func(inner consume2.Consumer[int]) consume2.Consumer[int] {
+		return consume2.Call(func(x int) {
+			for i := 0; i < x && inner.CanConsume(); i++ {
+				inner.Consume(x)
+			}
+		})
+	}
+	puller := consume2.NewPuller(
+		consume2.NewPuller(
+			cubesLessThan216(), consume2.PSlice[int](0, 3)).Next,
+		consume2.Join(repeat, consume2.PSlice[int](0, 6)))
+	var result []int
+	consume2.FromGenerator(puller.Next, consume2.AppendTo(&result))
+	assert.Equal([]int{1, 8, 8, 8, 8, 8}, result)
+}
+
+func TestPullerFinish(t *testing.T) {
+	assert := assert.New(t)
+	var tracker finishTracker[int]
+	puller := consume2.NewPuller(
+		cubesLessThan216(),
+		func(inner consume2.Consumer[int]) consume2.Consumer[int] {
+			return consume2.Compose[int](inner, &tracker)
+		})
+	var result []int
+	consume2.FromGenerator(puller.Next, consume2.AppendTo(&result))
+	assert.Equal([]int{1, 8, 27, 64, 125}, result)
+	assert.Equal(1, tracker.finished)
+}
+
+func TestPullerStop(t *testing.T) {
+	assert := assert.New(t)
+	var pulled int
+	generator := func() (int, bool) {
+		pulled++
+		return pulled, true
+	}
+	puller := consume2.NewPuller(generator, consume2.Identity[int]())
+	value, ok := puller.Next()
+	assert.True(ok)
+	assert.Equal(1, value)
+	puller.Stop()
+	_, ok = puller.Next()
+	assert.False(ok)
+	assert.Equal(1, pulled)
+}
+
+func TestPullerStopFinishes(t *testing.T) {
+	assert := assert.New(t)
+	var tracker finishTracker[int]
+	puller := consume2.NewPuller(
+		cubesLessThan216(),
+		func(inner consume2.Consumer[int]) consume2.Consumer[int] {
+			return consume2.Compose[int](inner, &tracker)
+		})
+	value, ok := puller.Next()
+	assert.True(ok)
+	assert.Equal(1, value)
+	puller.Stop()
+	assert.Equal(1, tracker.finished)
+	assert.Equal([]int{1}, tracker.values)
+	_, ok = puller.Next()
+	assert.False(ok)
+	puller.Stop()
+	assert.Equal(1, tracker.finished)
+}
+
+func TestPullerErr(t *testing.T) {
+	assert := assert.New(t)
+	errBad := errors.New("bad")
+	puller := consume2.NewPuller(
+		cubesLessThan216(),
+		consume2.PMapErr(func(x int) (int, error) {
+			if x > 8 {
+				return 0, errBad
+			}
+			return x, nil
+		}))
+	var result []int
+	consume2.FromGenerator(puller.Next, consume2.AppendTo(&result))
+	assert.Equal([]int{1, 8}, result)
+	assert.Equal(errBad, puller.Err())
+}
@@ -0,0 +1,294 @@
+package consume2
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+const quantilesVersion = 1
+
+// Quantiles[N] is a Consumer[N] that estimates quantiles of the N values
+// it consumes using a t-digest. A t-digest keeps a bounded number of
+// weighted centroids rather than the values themselves, so its memory use
+// does not grow with the number of values consumed. Estimates are most
+// accurate near the extreme quantiles. Quantiles[N] implements
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler.
+type Quantiles[N Number] struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+// NewQuantiles[N] creates a Quantiles[N]. compression controls the
+// trade-off between accuracy and memory; the number of centroids kept is
+// on the order of compression. 100 is a good default.
+// NewQuantiles[N] panics if compression is not positive.
+func NewQuantiles[N Number](compression float64) *Quantiles[N] {
+	if !(compression > 0) {
+		panic("compression must be positive")
+	}
+	return &Quantiles[N]{compression: compression}
+}
+
+// CanConsume always returns true.
+func (q *Quantiles[N]) CanConsume() bool {
+	return true
+}
+
+// Consume includes value in the sketch.
+func (q *Quantiles[N]) Consume(value N) {
+	q.add(centroid{mean: float64(value), weight: 1})
+}
+
+// Count returns the number of N values consumed.
+func (q *Quantiles[N]) Count() int {
+	return int(q.count)
+}
+
+// Quantile returns the estimated value at quantile p where p is between 0
+// and 1. Quantile(0.5) estimates the median. Quantile returns NaN if no
+// values were consumed.
+func (q *Quantiles[N]) Quantile(p float64) float64 {
+	q.compress()
+	if len(q.centroids) == 0 {
+		return math.NaN()
+	}
+	if p <= 0 {
+		return q.min
+	}
+	if p >= 1 {
+		return q.max
+	}
+	target := p * q.count
+	first := q.centroids[0]
+	if target < first.weight/2 {
+		return interpolate(
+			q.min, first.mean, target/(first.weight/2))
+	}
+	var cumulative float64
+	for i := 0; i < len(q.centroids)-1; i++ {
+		left, right := q.centroids[i], q.centroids[i+1]
+		leftCenter := cumulative + left.weight/2
+		rightCenter := cumulative + left.weight + right.weight/2
+		if target < rightCenter {
+			return interpolate(
+				left.mean,
+				right.mean,
+				(target-leftCenter)/(rightCenter-leftCenter))
+		}
+		cumulative += left.weight
+	}
+	last := q.centroids[len(q.centroids)-1]
+	lastCenter := q.count - last.weight/2
+	return interpolate(
+		last.mean, q.max, (target-lastCenter)/(last.weight/2))
+}
+
+// CDF returns the estimated fraction of consumed values that are less
+// than or equal to x. CDF returns NaN if no values were consumed.
+func (q *Quantiles[N]) CDF(x float64) float64 {
+	q.compress()
+	if len(q.centroids) == 0 {
+		return math.NaN()
+	}
+	if x < q.min {
+		return 0
+	}
+	if x >= q.max {
+		return 1
+	}
+	first := q.centroids[0]
+	if x < first.mean {
+		return fraction(x, q.min, first.mean) * first.weight / 2 / q.count
+	}
+	var cumulative float64
+	for i := 0; i < len(q.centroids)-1; i++ {
+		left, right := q.centroids[i], q.centroids[i+1]
+		if x < right.mean {
+			leftCenter := cumulative + left.weight/2
+			rightCenter := cumulative + left.weight + right.weight/2
+			rank := leftCenter +
+				fraction(x, left.mean, right.mean)*(rightCenter-leftCenter)
+			return rank / q.count
+		}
+		cumulative += left.weight
+	}
+	last := q.centroids[len(q.centroids)-1]
+	lastCenter := q.count - last.weight/2
+	rank := lastCenter + fraction(x, last.mean, q.max)*last.weight/2
+	return rank / q.count
+}
+
+// Merge combines the sketch in other into this instance as if this
+// instance had also consumed the N values that other consumed.
+func (q *Quantiles[N]) Merge(other *Quantiles[N]) {
+	other.compress()
+	for _, c := range other.centroids {
+		q.addWithRange(c, other.min, other.max)
+	}
+}
+
+// MarshalBinary encodes this sketch.
+func (q *Quantiles[N]) MarshalBinary() ([]byte, error) {
+	q.compress()
+	result := make([]byte, 0, 1+8*(5+2*len(q.centroids)))
+	result = append(result, quantilesVersion)
+	result = appendFloat64(result, q.compression)
+	result = appendFloat64(result, q.count)
+	result = appendFloat64(result, q.min)
+	result = appendFloat64(result, q.max)
+	result = appendUint64(result, uint64(len(q.centroids)))
+	for _, c := range q.centroids {
+		result = appendFloat64(result, c.mean)
+		result = appendFloat64(result, c.weight)
+	}
+	return result, nil
+}
+
+// UnmarshalBinary decodes a sketch that MarshalBinary encoded and stores it
+// in this instance.
+func (q *Quantiles[N]) UnmarshalBinary(data []byte) error {
+	if len(data) < 1+8*5 || data[0] != quantilesVersion {
+		return errBadQuantiles
+	}
+	data = data[1:]
+	var fields [5]uint64
+	for i := range fields {
+		fields[i] = binary.BigEndian.Uint64(data)
+		data = data[8:]
+	}
+	length := fields[4]
+	if length > uint64(len(data))/16 || uint64(len(data)) != 16*length {
+		return errBadQuantiles
+	}
+	compression := math.Float64frombits(fields[0])
+	count := math.Float64frombits(fields[1])
+	min := math.Float64frombits(fields[2])
+	max := math.Float64frombits(fields[3])
+	if !(compression > 0) || !(count >= 0) || math.IsNaN(min) ||
+		math.IsNaN(max) || (length > 0 && !(min <= max)) {
+		return errBadQuantiles
+	}
+	centroids := make([]centroid, length)
+	var total float64
+	for i := range centroids {
+		centroids[i].mean = math.Float64frombits(
+			binary.BigEndian.Uint64(data))
+		centroids[i].weight = math.Float64frombits(
+			binary.BigEndian.Uint64(data[8:]))
+		data = data[16:]
+		if !(centroids[i].weight > 0) || math.IsNaN(centroids[i].mean) {
+			return errBadQuantiles
+		}
+		if i > 0 && centroids[i].mean < centroids[i-1].mean {
+			return errBadQuantiles
+		}
+		total += centroids[i].weight
+	}
+	// count must match the total weight of the centroids up to rounding.
+	if !(math.Abs(total-count) <= 1e-9*math.Max(total, 1)) {
+		return errBadQuantiles
+	}
+	*q = Quantiles[N]{
+		compression: compression,
+		count:       count,
+		min:         min,
+		max:         max,
+		centroids:   centroids,
+	}
+	return nil
+}
+
+var errBadQuantiles = errors.New("consume2: malformed Quantiles data")
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+func (q *Quantiles[N]) add(c centroid) {
+	q.addWithRange(c, c.mean, c.mean)
+}
+
+func (q *Quantiles[N]) addWithRange(c centroid, min, max float64) {
+	if q.count == 0 || min < q.min {
+		q.min = min
+	}
+	if q.count == 0 || max > q.max {
+		q.max = max
+	}
+	q.count += c.weight
+	q.buffer = append(q.buffer, c)
+	if len(q.buffer) >= q.bufferSize() {
+		q.compress()
+	}
+}
+
+func (q *Quantiles[N]) bufferSize() int {
+	return int(5*q.compression) + 1
+}
+
+// compress merges the buffered centroids into the centroids of this
+// sketch using the k1 scale function of the t-digest paper.
+func (q *Quantiles[N]) compress() {
+	if len(q.buffer) == 0 {
+		return
+	}
+	all := append(q.buffer, q.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	merged := make([]centroid, 0, len(q.centroids)+1)
+	current := all[0]
+	var weightSoFar float64
+	kLeft := q.scale(0)
+	for _, c := range all[1:] {
+		proposed := (weightSoFar + current.weight + c.weight) / q.count
+		if q.scale(proposed)-kLeft <= 1 {
+			current.weight += c.weight
+			current.mean += (c.mean - current.mean) * c.weight / current.weight
+			continue
+		}
+		weightSoFar += current.weight
+		kLeft = q.scale(weightSoFar / q.count)
+		merged = append(merged, current)
+		current = c
+	}
+	q.centroids = append(merged, current)
+	q.buffer = q.buffer[:0]
+}
+
+func (q *Quantiles[N]) scale(p float64) float64 {
+	x := 2*p - 1
+	if x > 1 {
+		x = 1
+	}
+	if x < -1 {
+		x = -1
+	}
+	return q.compression / (2 * math.Pi) * math.Asin(x)
+}
+
+func interpolate(low, high, t float64) float64 {
+	return low + t*(high-low)
+}
+
+func fraction(x, low, high float64) float64 {
+	if high <= low {
+		return 1
+	}
+	return (x - low) / (high - low)
+}
+
+func appendFloat64(b []byte, x float64) []byte {
+	return appendUint64(b, math.Float64bits(x))
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
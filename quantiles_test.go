@@ -0,0 +1,114 @@
+package consume2_test
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantiles(t *testing.T) {
+	assert := assert.New(t)
+	quantiles := consume2.NewQuantiles[int](100)
+	consume2.FromSlice[int](shuffledInts(100000, 1), quantiles)
+	assert.Equal(100000, quantiles.Count())
+	assert.Equal(0.0, quantiles.Quantile(0))
+	assert.Equal(99999.0, quantiles.Quantile(1))
+	assert.InDelta(50000.0, quantiles.Quantile(0.5), 500)
+	assert.InDelta(99000.0, quantiles.Quantile(0.99), 100)
+	assert.InDelta(1000.0, quantiles.Quantile(0.01), 100)
+	assert.InDelta(0.25, quantiles.CDF(25000), 0.005)
+	assert.InDelta(0.999, quantiles.CDF(99900), 0.0005)
+	assert.Equal(0.0, quantiles.CDF(-1))
+	assert.Equal(1.0, quantiles.CDF(99999))
+}
+
+func TestQuantilesSmall(t *testing.T) {
+	assert := assert.New(t)
+	quantiles := consume2.NewQuantiles[float64](100)
+	assert.True(math.IsNaN(quantiles.Quantile(0.5)))
+	assert.True(math.IsNaN(quantiles.CDF(0)))
+	consume2.FromSlice[float64]([]float64{1, 2, 3, 4, 5}, quantiles)
+	assert.Equal(3.0, quantiles.Quantile(0.5))
+	assert.Equal(0.5, quantiles.CDF(3))
+}
+
+func TestQuantilesMerge(t *testing.T) {
+	assert := assert.New(t)
+	values := shuffledInts(100000, 2)
+	first := consume2.NewQuantiles[int](100)
+	second := consume2.NewQuantiles[int](100)
+	consume2.FromSlice[int](values[:30000], first)
+	consume2.FromSlice[int](values[30000:], second)
+	first.Merge(second)
+	assert.Equal(100000, first.Count())
+	assert.Equal(0.0, first.Quantile(0))
+	assert.Equal(99999.0, first.Quantile(1))
+	assert.InDelta(50000.0, first.Quantile(0.5), 500)
+	assert.InDelta(90000.0, first.Quantile(0.9), 300)
+}
+
+func TestQuantilesMarshal(t *testing.T) {
+	assert := assert.New(t)
+	quantiles := consume2.NewQuantiles[int](50)
+	consume2.FromSlice[int](shuffledInts(10000, 3), quantiles)
+	data, err := quantiles.MarshalBinary()
+	assert.NoError(err)
+	var decoded consume2.Quantiles[int]
+	assert.NoError(decoded.UnmarshalBinary(data))
+	assert.Equal(quantiles.Count(), decoded.Count())
+	assert.Equal(quantiles.Quantile(0.3), decoded.Quantile(0.3))
+	decoded.Consume(20000)
+	assert.Equal(20000.0, decoded.Quantile(1))
+	assert.Error(decoded.UnmarshalBinary(data[:len(data)-1]))
+	assert.Error(decoded.UnmarshalBinary(nil))
+}
+
+func TestQuantilesUnmarshalCorrupt(t *testing.T) {
+	assert := assert.New(t)
+	quantiles := consume2.NewQuantiles[int](50)
+	consume2.FromSlice[int]([]int{5, 1, 9, 3}, quantiles)
+	data, err := quantiles.MarshalBinary()
+	assert.NoError(err)
+	var decoded consume2.Quantiles[int]
+
+	// The centroid count is the last field in the 41 byte header.
+	hugeLength := make([]byte, 41)
+	copy(hugeLength, data[:33])
+	binary.BigEndian.PutUint64(hugeLength[33:], 1<<60)
+	assert.Error(decoded.UnmarshalBinary(hugeLength))
+
+	corrupt := func(offset int, value float64) []byte {
+		result := append([]byte(nil), data...)
+		binary.BigEndian.PutUint64(result[offset:], math.Float64bits(value))
+		return result
+	}
+	assert.Error(decoded.UnmarshalBinary(corrupt(9, -1)))
+	assert.Error(decoded.UnmarshalBinary(corrupt(9, math.NaN())))
+	assert.Error(decoded.UnmarshalBinary(corrupt(17, math.NaN())))
+	assert.Error(decoded.UnmarshalBinary(corrupt(25, math.NaN())))
+	assert.Error(decoded.UnmarshalBinary(corrupt(17, 100)))
+
+	// count no longer matches the total weight of the centroids.
+	assert.Error(decoded.UnmarshalBinary(corrupt(9, 0)))
+	assert.Error(decoded.UnmarshalBinary(corrupt(9, 5)))
+	assert.Error(decoded.UnmarshalBinary(corrupt(9, math.Inf(1))))
+
+	// Swap the first two centroids so they are no longer sorted by mean.
+	unsorted := append([]byte(nil), data...)
+	copy(unsorted[41:57], data[57:73])
+	copy(unsorted[57:73], data[41:57])
+	assert.Error(decoded.UnmarshalBinary(unsorted))
+	assert.NoError(decoded.UnmarshalBinary(data))
+}
+
+func TestNewQuantilesPanics(t *testing.T) {
+	assert.Panics(t, func() { consume2.NewQuantiles[int](0) })
+}
+
+func shuffledInts(n int, seed int64) []int {
+	return rand.New(rand.NewSource(seed)).Perm(n)
+}
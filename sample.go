@@ -0,0 +1,156 @@
+package consume2
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Sample[T] is a Consumer[T] that keeps a uniform random sample of the T
+// values it consumes without knowing in advance how many values there
+// are. Sample[T] uses reservoir sampling with Algorithm L, so it draws
+// random numbers only for the values it keeps.
+type Sample[T any] struct {
+	rand   *rand.Rand
+	values []T
+	n      int
+	count  int
+	w      float64
+	next   int
+}
+
+// NewSample[T] creates a Sample[T] that keeps n values. source supplies
+// the randomness; passing a source with a fixed seed makes the sample
+// deterministic. NewSample[T] panics if n <= 0.
+func NewSample[T any](n int, source rand.Source) *Sample[T] {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	return &Sample[T]{rand: rand.New(source), values: make([]T, 0, n), n: n}
+}
+
+// CanConsume always returns true.
+func (s *Sample[T]) CanConsume() bool {
+	return true
+}
+
+// Consume consumes a single T value.
+func (s *Sample[T]) Consume(value T) {
+	index := s.count
+	s.count++
+	if len(s.values) < s.n {
+		s.values = append(s.values, value)
+		if len(s.values) == s.n {
+			s.w = math.Exp(math.Log(uniform(s.rand)) / float64(s.n))
+			s.advance(index)
+		}
+		return
+	}
+	if index == s.next {
+		s.values[s.rand.Intn(s.n)] = value
+		s.w *= math.Exp(math.Log(uniform(s.rand)) / float64(s.n))
+		s.advance(index)
+	}
+}
+
+// Build returns the sampled T values. Build returns all the consumed T
+// values if fewer than n values were consumed.
+func (s *Sample[T]) Build() []T {
+	result := make([]T, len(s.values))
+	copy(result, s.values)
+	return result
+}
+
+// advance picks the index of the next value to keep.
+func (s *Sample[T]) advance(index int) {
+	skip := math.Floor(math.Log(uniform(s.rand)) / math.Log1p(-s.w))
+	if skip >= float64(math.MaxInt-index-1) {
+		s.next = math.MaxInt
+		return
+	}
+	s.next = index + int(skip) + 1
+}
+
+// WeightedSample[T] is a Consumer[T] that keeps a weighted random sample
+// of the T values it consumes. The chance that a value is kept is
+// proportional to its weight. WeightedSample[T] uses the A-Res algorithm.
+type WeightedSample[T any] struct {
+	rand   *rand.Rand
+	weight func(T) float64
+	heap   heap[weightedValue[T]]
+	n      int
+}
+
+// NewWeightedSample[T] creates a WeightedSample[T] that keeps n values.
+// weight returns the weight of a T value. T values with a weight that is
+// not positive are never kept. source supplies the randomness.
+// NewWeightedSample[T] panics if n <= 0.
+func NewWeightedSample[T any](
+	n int, weight func(T) float64, source rand.Source) *WeightedSample[T] {
+	if n <= 0 {
+		panic("n must be positive")
+	}
+	return &WeightedSample[T]{
+		rand:   rand.New(source),
+		weight: weight,
+		heap:   heap[weightedValue[T]]{less: lessWeightedValue[T]},
+		n:      n,
+	}
+}
+
+// CanConsume always returns true.
+func (w *WeightedSample[T]) CanConsume() bool {
+	return true
+}
+
+// Consume consumes a single T value.
+func (w *WeightedSample[T]) Consume(value T) {
+	weight := w.weight(value)
+	if !(weight > 0) {
+		return
+	}
+	// log(u^(1/weight)) orders values the same way as u^(1/weight) but
+	// does not underflow for large weights.
+	entry := weightedValue[T]{
+		key: math.Log(uniform(w.rand)) / weight, value: value}
+	if w.heap.Len() < w.n {
+		w.heap.Push(entry)
+		return
+	}
+	if w.heap.First().key < entry.key {
+		w.heap.ReplaceFirst(entry)
+	}
+}
+
+// Build returns the sampled T values. Build returns all the consumed T
+// values with positive weight if there are fewer than n of them.
+func (w *WeightedSample[T]) Build() []T {
+	entries := make([]weightedValue[T], len(w.heap.values))
+	copy(entries, w.heap.values)
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[j].key < entries[i].key
+	})
+	result := make([]T, len(entries))
+	for i := range entries {
+		result[i] = entries[i].value
+	}
+	return result
+}
+
+type weightedValue[T any] struct {
+	key   float64
+	value T
+}
+
+func lessWeightedValue[T any](a, b weightedValue[T]) bool {
+	return a.key < b.key
+}
+
+// uniform returns a random number greater than 0 and less than 1.
+func uniform(r *rand.Rand) float64 {
+	for {
+		if u := r.Float64(); u > 0 {
+			return u
+		}
+	}
+}
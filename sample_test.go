@@ -0,0 +1,75 @@
+package consume2_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleDeterministic(t *testing.T) {
+	assert := assert.New(t)
+	first := consume2.NewSample[int](5, rand.NewSource(7))
+	second := consume2.NewSample[int](5, rand.NewSource(7))
+	consume2.FromSlice[int](repeatInts(10000, 1), first)
+	consume2.FromSlice[int](repeatInts(10000, 1), second)
+	assert.Len(first.Build(), 5)
+	assert.Equal(first.Build(), second.Build())
+}
+
+func TestSampleFewValues(t *testing.T) {
+	sample := consume2.NewSample[int](5, rand.NewSource(1))
+	consume2.FromSlice[int]([]int{1, 2, 3}, sample)
+	assert.Equal(t, []int{1, 2, 3}, sample.Build())
+}
+
+func TestSampleUniform(t *testing.T) {
+	const trials = 20000
+	counts := make([]int, 10)
+	source := rand.NewSource(11)
+	for i := 0; i < trials; i++ {
+		sample := consume2.NewSample[int](3, source)
+		consume2.FromSlice[int](repeatInts(10, 1), sample)
+		for _, value := range sample.Build() {
+			counts[value]++
+		}
+	}
+	for _, count := range counts {
+		assert.InDelta(t, 0.3, float64(count)/trials, 0.02)
+	}
+}
+
+func TestWeightedSample(t *testing.T) {
+	const trials = 20000
+	source := rand.NewSource(13)
+	weight := func(x int) float64 { return float64(x) }
+	var nines int
+	for i := 0; i < trials; i++ {
+		sample := consume2.NewWeightedSample(1, weight, source)
+		consume2.FromSlice[int]([]int{0, 1, 9, -3}, sample)
+		result := sample.Build()
+		assert.Len(t, result, 1)
+		if result[0] == 9 {
+			nines++
+		}
+	}
+	assert.InDelta(t, 0.9, float64(nines)/trials, 0.02)
+}
+
+func TestWeightedSampleFewValues(t *testing.T) {
+	sample := consume2.NewWeightedSample(
+		5, func(x int) float64 { return 1 }, rand.NewSource(1))
+	consume2.FromSlice[int]([]int{1, 2}, sample)
+	assert.ElementsMatch(t, []int{1, 2}, sample.Build())
+}
+
+func TestNewSamplePanics(t *testing.T) {
+	assert.Panics(t, func() {
+		consume2.NewSample[int](0, rand.NewSource(1))
+	})
+	assert.Panics(t, func() {
+		consume2.NewWeightedSample(
+			0, func(x int) float64 { return 1 }, rand.NewSource(1))
+	})
+}
@@ -0,0 +1,83 @@
+//go:build go1.23
+
+package consume2
+
+import (
+	"iter"
+)
+
+// Pair[K,V] holds a key value pair. FromSeq2 sends the key value pairs of
+// an iter.Seq2[K,V] to a consumer as Pair[K,V] values.
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// FromSeq sends the values seq yields to consumer. FromSeq stops seq early
+// when consumer can consume no more values. FromSeq calls Finish on
+// consumer after sending the last value and returns the error consumer
+// encountered, if any.
+func FromSeq[T any](seq iter.Seq[T], consumer Consumer[T]) error {
+	if consumer.CanConsume() {
+		seq(AsFunc(consumer))
+	}
+	Finish(consumer)
+	return Err(consumer)
+}
+
+// FromSeq2 works like FromSeq except that it sends the key value pairs
+// seq yields to consumer as Pair[K,V] values.
+func FromSeq2[K, V any](
+	seq iter.Seq2[K, V], consumer Consumer[Pair[K, V]]) error {
+	if consumer.CanConsume() {
+		seq(func(key K, value V) bool {
+			consumer.Consume(Pair[K, V]{Key: key, Value: value})
+			return consumer.CanConsume()
+		})
+	}
+	Finish(consumer)
+	return Err(consumer)
+}
+
+// SeqSource returns a Source that sends the values seq yields.
+func SeqSource[T any](seq iter.Seq[T]) Source[T] {
+	return func(consumer Consumer[T]) error {
+		return FromSeq(seq, consumer)
+	}
+}
+
+// Seq2Source returns a Source that sends the key value pairs seq yields.
+func Seq2Source[K, V any](seq iter.Seq2[K, V]) Source[Pair[K, V]] {
+	return func(consumer Consumer[Pair[K, V]]) error {
+		return FromSeq2(seq, consumer)
+	}
+}
+
+// Seq returns an iter.Seq[U] that yields the U values this pipeline emits
+// when it consumes the T values from seq. The returned iter.Seq[U] stops
+// seq early when this pipeline can consume no more T values or when the
+// loop ranging over it exits. Seq drops any error this pipeline encounters;
+// use FromSeq with Run to observe errors.
+func (p Pipeline[T, U]) Seq(seq iter.Seq[T]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		FromSeq(seq, p.Run(&yieldConsumer[U]{yield: yield}))
+	}
+}
+
+type yieldConsumer[T any] struct {
+	yield func(T) bool
+	done  bool
+}
+
+func (y *yieldConsumer[T]) CanConsume() bool {
+	return !y.done
+}
+
+func (y *yieldConsumer[T]) Consume(value T) {
+	if y.done {
+		return
+	}
+	if !y.yield(value) {
+		y.done = true
+	}
+}
@@ -0,0 +1,78 @@
+//go:build go1.23
+
+package consume2_test
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromSeq(t *testing.T) {
+	var firstThree []int
+	err := consume2.FromSeq(
+		naturals(), consume2.Slice(consume2.AppendTo(&firstThree), 0, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, firstThree)
+}
+
+func TestFromSeqFinishes(t *testing.T) {
+	var tracker finishTracker[int]
+	consume2.FromSeq[int](slices.Values([]int{3, 5}), &tracker)
+	assert.Equal(t, []int{3, 5}, tracker.values)
+	assert.Equal(t, 1, tracker.finished)
+}
+
+func TestFromSeq2(t *testing.T) {
+	var pairs []consume2.Pair[string, int]
+	err := consume2.FromSeq2(
+		maps.All(map[string]int{"one": 1}), consume2.AppendTo(&pairs))
+	assert.NoError(t, err)
+	assert.Equal(t, []consume2.Pair[string, int]{{Key: "one", Value: 1}}, pairs)
+}
+
+func TestSeqSource(t *testing.T) {
+	values, err := consume2.SeqSource(naturals()).Take(3).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, values)
+	pairs, err := consume2.Seq2Source(slices.All([]string{"a"})).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []consume2.Pair[int, string]{{Key: 0, Value: "a"}}, pairs)
+}
+
+func TestPipelineSeq(t *testing.T) {
+	pipeline := consume2.Join(
+		consume2.PFilter(func(x int) bool { return x%2 == 1 }),
+		consume2.PMap(strconv.Itoa))
+	pipeline = consume2.Join(pipeline, consume2.PSlice[string](0, 4))
+	var result []string
+	for s := range pipeline.Seq(naturals()) {
+		result = append(result, s)
+	}
+	assert.Equal(t, []string{"1", "3", "5", "7"}, result)
+}
+
+func TestPipelineSeqBreak(t *testing.T) {
+	var result []int
+	for x := range consume2.Identity[int]().Seq(naturals()) {
+		if x == 3 {
+			break
+		}
+		result = append(result, x)
+	}
+	assert.Equal(t, []int{0, 1, 2}, result)
+}
+
+func naturals() func(yield func(int) bool) {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
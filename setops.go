@@ -0,0 +1,150 @@
+package consume2
+
+// SetMode controls how Union, Intersect, Difference, and
+// SymmetricDifference treat repeated values within a sorted stream.
+type SetMode int
+
+const (
+
+	// SetSemantics treats each sorted stream as a set. A value that
+	// repeats within a stream counts once, and the result has each value
+	// at most once.
+	SetSemantics SetMode = iota
+
+	// MultisetSemantics treats each sorted stream as a multiset. A value
+	// that appears n times in a stream counts n times.
+	MultisetSemantics
+)
+
+// Union returns a Source that sends the union of the sorted T values from
+// generators. Each generator returns its T values in sorted order
+// according to less and returns false when it has no more T values. With
+// MultisetSemantics, a value appears as many times as it appears in the
+// generator that has it the most. The returned source reads each
+// generator once in a single pass and can be run only once.
+func Union[T any](
+	mode SetMode,
+	less func(a, b T) bool,
+	generators ...func() (T, bool)) Source[T] {
+	return setOperation(mode, less, generators, func(counts []int) int {
+		result := 0
+		for _, count := range counts {
+			if count > result {
+				result = count
+			}
+		}
+		return result
+	})
+}
+
+// Intersect returns a Source that sends the intersection of the sorted T
+// values from generators. With MultisetSemantics, a value appears as many
+// times as it appears in the generator that has it the least. See Union.
+func Intersect[T any](
+	mode SetMode,
+	less func(a, b T) bool,
+	generators ...func() (T, bool)) Source[T] {
+	return setOperation(mode, less, generators, func(counts []int) int {
+		if len(counts) == 0 {
+			return 0
+		}
+		result := counts[0]
+		for _, count := range counts[1:] {
+			if count < result {
+				result = count
+			}
+		}
+		return result
+	})
+}
+
+// Difference returns a Source that sends the sorted T values from first
+// that are not in any of the others. With MultisetSemantics, each
+// appearance of a value in the others cancels one appearance of that
+// value in first. See Union.
+func Difference[T any](
+	mode SetMode,
+	less func(a, b T) bool,
+	first func() (T, bool),
+	others ...func() (T, bool)) Source[T] {
+	generators := make([]func() (T, bool), 0, len(others)+1)
+	generators = append(generators, first)
+	generators = append(generators, others...)
+	return setOperation(mode, less, generators, func(counts []int) int {
+		result := counts[0]
+		for _, count := range counts[1:] {
+			result -= count
+		}
+		if result < 0 {
+			return 0
+		}
+		return result
+	})
+}
+
+// SymmetricDifference returns a Source that sends the sorted T values that
+// are in either a or b but not both. With MultisetSemantics, a value
+// appears as many times as the difference between the number of times it
+// appears in a and in b. See Union.
+func SymmetricDifference[T any](
+	mode SetMode,
+	less func(a, b T) bool,
+	a, b func() (T, bool)) Source[T] {
+	generators := []func() (T, bool){a, b}
+	return setOperation(mode, less, generators, func(counts []int) int {
+		if counts[0] > counts[1] {
+			return counts[0] - counts[1]
+		}
+		return counts[1] - counts[0]
+	})
+}
+
+// setOperation returns a Source that walks generators in lock step. For
+// each distinct value, it counts how many times each generator has that
+// value and sends that value as many times as count returns. Among equal
+// values, setOperation sends the one from the earliest generator.
+func setOperation[T any](
+	mode SetMode,
+	less func(a, b T) bool,
+	generators []func() (T, bool),
+	count func(counts []int) int) Source[T] {
+	generatorList := make([]func() (T, bool), len(generators))
+	copy(generatorList, generators)
+	return func(consumer Consumer[T]) error {
+		heads := make([]T, len(generatorList))
+		ok := make([]bool, len(generatorList))
+		counts := make([]int, len(generatorList))
+		if consumer.CanConsume() {
+			for i, generator := range generatorList {
+				heads[i], ok[i] = generator()
+			}
+		}
+		for consumer.CanConsume() {
+			smallest := -1
+			for i := range heads {
+				if ok[i] && (smallest < 0 || less(heads[i], heads[smallest])) {
+					smallest = i
+				}
+			}
+			if smallest < 0 {
+				break
+			}
+			value := heads[smallest]
+			for i, generator := range generatorList {
+				counts[i] = 0
+				for ok[i] && !less(value, heads[i]) {
+					counts[i]++
+					heads[i], ok[i] = generator()
+				}
+				if mode == SetSemantics && counts[i] > 1 {
+					counts[i] = 1
+				}
+			}
+			for n := count(counts); n > 0 && consumer.CanConsume(); n-- {
+				consumer.Consume(value)
+			}
+		}
+		Finish(consumer)
+		return Err(consumer)
+	}
+}
@@ -0,0 +1,76 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	setA = []int{1, 2, 2, 2, 4, 5, 5, 9}
+	setB = []int{2, 2, 3, 5, 9, 9}
+	setC = []int{2, 5, 5, 5, 7}
+)
+
+func TestUnion(t *testing.T) {
+	assertSource(t, []int{1, 2, 3, 4, 5, 7, 9}, consume2.Union(
+		consume2.SetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB), sliceGenerator(setC)))
+	assertSource(t, []int{1, 2, 2, 2, 3, 4, 5, 5, 5, 7, 9, 9}, consume2.Union(
+		consume2.MultisetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB), sliceGenerator(setC)))
+	assertSource(t, nil, consume2.Union(consume2.SetSemantics, intLess))
+}
+
+func TestIntersect(t *testing.T) {
+	assertSource(t, []int{2, 5}, consume2.Intersect(
+		consume2.SetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB), sliceGenerator(setC)))
+	assertSource(t, []int{2, 2, 5, 9}, consume2.Intersect(
+		consume2.MultisetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB)))
+	assertSource(t, nil, consume2.Intersect(consume2.SetSemantics, intLess))
+}
+
+func TestDifference(t *testing.T) {
+	assertSource(t, []int{1, 4}, consume2.Difference(
+		consume2.SetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB), sliceGenerator(setC)))
+	assertSource(t, []int{1, 2, 4, 5}, consume2.Difference(
+		consume2.MultisetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB)))
+	assertSource(t, []int{1, 2, 4, 5, 9}, consume2.Difference(
+		consume2.SetSemantics, intLess, sliceGenerator(setA)))
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	assertSource(t, []int{1, 3, 4}, consume2.SymmetricDifference(
+		consume2.SetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB)))
+	assertSource(t, []int{1, 2, 3, 4, 5, 9}, consume2.SymmetricDifference(
+		consume2.MultisetSemantics, intLess,
+		sliceGenerator(setA), sliceGenerator(setB)))
+}
+
+func TestSetOperationStopsEarly(t *testing.T) {
+	var pulls int
+	counting := func(values []int) func() (int, bool) {
+		g := sliceGenerator(values)
+		return func() (int, bool) {
+			pulls++
+			return g()
+		}
+	}
+	assertSource(t, []int{1, 2}, consume2.Union(
+		consume2.SetSemantics, intLess,
+		counting(setA), counting(setB)).Take(2))
+	assert.Equal(t, 8, pulls)
+}
+
+func assertSource(t *testing.T, expected []int, source consume2.Source[int]) {
+	t.Helper()
+	values, err := source.Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, values)
+}
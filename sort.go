@@ -0,0 +1,291 @@
+package consume2
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"sort"
+)
+
+// Sort[T] returns a Consumer[T] that collects the T values it consumes and
+// sends them in sorted order to the underlying consumer when finished.
+// less reports whether a is less than b. The sort is stable. Sort[T]
+// keeps all the T values in memory; use ExternalSort[T] for more values
+// than fit in memory.
+func Sort[T any](consumer Consumer[T], less func(a, b T) bool) Consumer[T] {
+	return &sortConsumer[T]{consumer: consumer, less: less}
+}
+
+// ExternalSort[T] works like Sort[T] except that it keeps at most
+// maxInMemory T values in memory. Each time it collects maxInMemory T
+// values, it sorts them and writes them to a temporary file in dir using
+// codec. When finished, it merges the sorted files reading only a limited
+// number of them at once, so the number of temporary files can exceed the
+// limit on open files. If dir is empty, ExternalSort[T] uses the default
+// directory for temporary files. ExternalSort[T] removes its temporary
+// files only when finished, so callers must always finish the returned
+// consumer, even when abandoning it early. If the underlying consumer no
+// longer accepts values by then, ExternalSort[T] skips the merge. The Err
+// method of returned consumer reports any I/O or encoding error.
+// ExternalSort[T] panics if maxInMemory <= 0.
+func ExternalSort[T any](
+	consumer Consumer[T],
+	less func(a, b T) bool,
+	maxInMemory int,
+	codec Codec[T],
+	dir string) Consumer[T] {
+	checkExternalSort(maxInMemory)
+	return &sortConsumer[T]{
+		consumer:    consumer,
+		less:        less,
+		maxInMemory: maxInMemory,
+		codec:       codec,
+		dir:         dir,
+	}
+}
+
+// PSort returns a Pipeline that emits the T values it receives in sorted
+// order once there are no more T values. See Sort.
+func PSort[T any](less func(a, b T) bool) Pipeline[T, T] {
+	return func(inner Consumer[T]) Consumer[T] {
+		return Sort(inner, less)
+	}
+}
+
+// PExternalSort works like PSort except that it spills sorted runs of T
+// values to temporary files. See ExternalSort.
+func PExternalSort[T any](
+	less func(a, b T) bool,
+	maxInMemory int,
+	codec Codec[T],
+	dir string) Pipeline[T, T] {
+	checkExternalSort(maxInMemory)
+	return func(inner Consumer[T]) Consumer[T] {
+		return ExternalSort(inner, less, maxInMemory, codec, dir)
+	}
+}
+
+type sortConsumer[T any] struct {
+	consumer    Consumer[T]
+	less        func(a, b T) bool
+	values      []T
+	maxInMemory int
+	codec       Codec[T]
+	dir         string
+	runs        []string
+	err         error
+}
+
+// sortMergeFanIn is the most temporary files that ExternalSort[T] reads
+// at once.
+const sortMergeFanIn = 64
+
+func (s *sortConsumer[T]) CanConsume() bool {
+	return s.err == nil && s.consumer.CanConsume()
+}
+
+func (s *sortConsumer[T]) Consume(value T) {
+	if !s.CanConsume() {
+		return
+	}
+	s.values = append(s.values, value)
+	if s.maxInMemory > 0 && len(s.values) >= s.maxInMemory {
+		s.err = s.spill()
+	}
+}
+
+func (s *sortConsumer[T]) Finish() {
+	defer s.removeRuns()
+	if s.err == nil && s.consumer.CanConsume() {
+		sort.SliceStable(s.values, s.lessIndex)
+		if len(s.runs) == 0 {
+			s.emit(s.memoryRun())
+		} else if err := s.merge(); err != nil {
+			s.err = err
+		}
+	}
+	s.values = nil
+	Finish(s.consumer)
+}
+
+func (s *sortConsumer[T]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return Err(s.consumer)
+}
+
+func (s *sortConsumer[T]) lessIndex(i, j int) bool {
+	return s.less(s.values[i], s.values[j])
+}
+
+// spill writes the sorted values in memory to a new temporary file.
+func (s *sortConsumer[T]) spill() error {
+	sort.SliceStable(s.values, s.lessIndex)
+	name, err := s.writeRun(s.memoryRun())
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, name)
+	var zero T
+	for i := range s.values {
+		s.values[i] = zero
+	}
+	s.values = s.values[:0]
+	return nil
+}
+
+// writeRun writes the values from generator to a new temporary file and
+// returns its name. On error, writeRun removes the file.
+func (s *sortConsumer[T]) writeRun(generator func() (T, bool)) (
+	name string, err error) {
+	file, err := os.CreateTemp(s.dir, "consume2-sort-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	writer := bufio.NewWriter(file)
+	encoder := s.codec.NewEncoder(writer)
+	for value, ok := generator(); ok; value, ok = generator() {
+		if err := encoder.Encode(value); err != nil {
+			return "", err
+		}
+	}
+	if s.err != nil {
+		return "", s.err
+	}
+	if err := writer.Flush(); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// emit sends the values from generator to the underlying consumer.
+func (s *sortConsumer[T]) emit(generator func() (T, bool)) {
+	for s.CanConsume() {
+		value, ok := generator()
+		if !ok {
+			return
+		}
+		s.consumer.Consume(value)
+	}
+}
+
+// merge sends the values in the temporary files and the values still in
+// memory to the underlying consumer in sorted order. While there are too
+// many temporary files to read at once, merge makes passes that combine
+// each group of files into a single file. The values in memory came last,
+// so they go last among equal values.
+func (s *sortConsumer[T]) merge() error {
+	for len(s.runs) >= sortMergeFanIn {
+		if err := s.mergePass(); err != nil {
+			return err
+		}
+	}
+	return s.readRuns(s.runs, func(generators []func() (T, bool)) error {
+		generators = append(generators, s.memoryRun())
+		s.emit(mergeGenerators(s.less, generators))
+		return nil
+	})
+}
+
+// mergePass combines each group of sortMergeFanIn temporary files into a
+// single temporary file. The combined files stay in the same order as the
+// groups, so equal values keep their order.
+func (s *sortConsumer[T]) mergePass() error {
+	var merged []string
+	defer func() {
+		s.runs = append(merged, s.runs...)
+	}()
+	for len(s.runs) > 0 {
+		size := sortMergeFanIn
+		if size > len(s.runs) {
+			size = len(s.runs)
+		}
+		group := s.runs[:size]
+		if size == 1 {
+			merged = append(merged, group[0])
+			s.runs = s.runs[size:]
+			continue
+		}
+		var name string
+		err := s.readRuns(group, func(generators []func() (T, bool)) error {
+			var err error
+			name, err = s.writeRun(mergeGenerators(s.less, generators))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, run := range group {
+			os.Remove(run)
+		}
+		merged = append(merged, name)
+		s.runs = s.runs[size:]
+	}
+	return nil
+}
+
+// readRuns opens the temporary files in runs and calls f with a generator
+// of the values in each. readRuns closes the files before returning.
+func (s *sortConsumer[T]) readRuns(
+	runs []string, f func(generators []func() (T, bool)) error) error {
+	generators := make([]func() (T, bool), 0, len(runs)+1)
+	for _, run := range runs {
+		file, err := os.Open(run)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		generators = append(generators, s.decodeRun(file))
+	}
+	return f(generators)
+}
+
+func (s *sortConsumer[T]) memoryRun() func() (T, bool) {
+	index := 0
+	return func() (value T, ok bool) {
+		if index == len(s.values) {
+			return
+		}
+		value = s.values[index]
+		index++
+		return value, true
+	}
+}
+
+func (s *sortConsumer[T]) decodeRun(file *os.File) func() (T, bool) {
+	decoder := s.codec.NewDecoder(bufio.NewReader(file))
+	return func() (value T, ok bool) {
+		if s.err != nil {
+			return
+		}
+		if err := decoder.Decode(&value); err != nil {
+			if err != io.EOF {
+				s.err = err
+			}
+			return
+		}
+		return value, true
+	}
+}
+
+func (s *sortConsumer[T]) removeRuns() {
+	for _, run := range s.runs {
+		os.Remove(run)
+	}
+	s.runs = nil
+}
+
+func checkExternalSort(maxInMemory int) {
+	if maxInMemory <= 0 {
+		panic("maxInMemory must be positive")
+	}
+}
@@ -0,0 +1,167 @@
+package consume2_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPSort(t *testing.T) {
+	assert := assert.New(t)
+	pipeline := consume2.Join(
+		consume2.PSort(intLess), consume2.PSlice[int](0, 5))
+	var result []int
+	err := consume2.FromSlice(shuffledInts(100, 6), pipeline.AppendTo(&result))
+	assert.NoError(err)
+	assert.Equal([]int{0, 1, 2, 3, 4}, result)
+}
+
+func TestPSortStable(t *testing.T) {
+	assert := assert.New(t)
+	var result []person
+	consume2.FromSlice(
+		people,
+		consume2.PSort(func(a, b person) bool {
+			return a.Age/10 < b.Age/10
+		}).AppendTo(&result))
+	assert.Equal(
+		[]person{
+			people[dillon], people[stoney], people[matt],
+			people[mark], people[beth],
+		},
+		result)
+}
+
+func TestPExternalSort(t *testing.T) {
+	for _, codec := range []consume2.Codec[person]{
+		consume2.GobCodec[person](), consume2.JSONCodec[person]()} {
+		assert := assert.New(t)
+		dir := t.TempDir()
+		var values []person
+		for _, x := range shuffledInts(1000, 7) {
+			values = append(values, person{Name: strconv.Itoa(x), Age: x / 3})
+		}
+		byAge := func(a, b person) bool { return a.Age < b.Age }
+		var expected []person
+		consume2.FromSlice(values, consume2.PSort(byAge).AppendTo(&expected))
+		var result []person
+		err := consume2.FromSlice(
+			values,
+			consume2.PExternalSort(byAge, 64, codec, dir).AppendTo(&result))
+		assert.NoError(err)
+		assert.Equal(expected, result)
+		assertEmptyDir(t, dir)
+	}
+}
+
+func TestPExternalSortManyRuns(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	var values []person
+	for _, x := range shuffledInts(1000, 9) {
+		values = append(values, person{Name: strconv.Itoa(x), Age: x / 5})
+	}
+	byAge := func(a, b person) bool { return a.Age < b.Age }
+	var expected []person
+	consume2.FromSlice(values, consume2.PSort(byAge).AppendTo(&expected))
+	var result []person
+	err := consume2.FromSlice(
+		values,
+		consume2.PExternalSort(
+			byAge, 3, consume2.GobCodec[person](), dir).AppendTo(&result))
+	assert.NoError(err)
+	assert.Equal(expected, result)
+	assertEmptyDir(t, dir)
+}
+
+func TestPExternalSortPullerStop(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	var sorted []int
+	puller := consume2.NewPuller(
+		func() (int, bool) { return 1, true },
+		func(inner consume2.Consumer[int]) consume2.Consumer[int] {
+			return consume2.Tee(
+				inner,
+				consume2.ExternalSort(
+					consume2.AppendTo(&sorted),
+					intLess,
+					10,
+					consume2.GobCodec[int](),
+					dir))
+		})
+	for i := 0; i < 25; i++ {
+		puller.Next()
+	}
+	entries, err := os.ReadDir(dir)
+	assert.NoError(err)
+	assert.Len(entries, 2)
+	puller.Stop()
+	_, ok := puller.Next()
+	assert.False(ok)
+	assert.Len(sorted, 25)
+	assertEmptyDir(t, dir)
+}
+
+func TestPExternalSortStopsEarly(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	pipeline := consume2.Join(
+		consume2.PExternalSort(intLess, 10, consume2.GobCodec[int](), dir),
+		consume2.PSlice[int](0, 3))
+	var result []int
+	err := consume2.FromSlice(shuffledInts(95, 8), pipeline.AppendTo(&result))
+	assert.NoError(err)
+	assert.Equal([]int{0, 1, 2}, result)
+	assertEmptyDir(t, dir)
+}
+
+func TestPExternalSortErr(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	var result []int
+	consumer := consume2.PExternalSort[int](
+		intLess, 2, failingCodec{}, dir).AppendTo(&result)
+	err := consume2.FromSlice([]int{3, 2, 1}, consumer)
+	assert.Equal(errEncode, err)
+	assert.Empty(result)
+	assertEmptyDir(t, dir)
+}
+
+func TestPExternalSortPanics(t *testing.T) {
+	assert.Panics(t, func() {
+		consume2.PExternalSort(intLess, 0, consume2.GobCodec[int](), "")
+	})
+}
+
+var errEncode = errors.New("encode failed")
+
+type failingCodec struct{}
+
+func (failingCodec) NewEncoder(w io.Writer) consume2.Encoder[int] {
+	return failingCodec{}
+}
+
+func (failingCodec) NewDecoder(r io.Reader) consume2.Decoder[int] {
+	return nil
+}
+
+func (failingCodec) Encode(value int) error {
+	return errEncode
+}
+
+func intLess(a, b int) bool {
+	return a < b
+}
+
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,107 @@
+package consume2
+
+import (
+	"context"
+)
+
+// Source[T] is an abstraction that sends a group of T values to a
+// consumer. A Source[T] stops early when the consumer can consume no more
+// values. When done, a Source[T] calls Finish on the consumer and returns
+// the error the consumer encountered, if any. Sources built from
+// generators can be run only once.
+type Source[T any] func(consumer Consumer[T]) error
+
+// SliceSource returns a Source that sends the values in aslice.
+func SliceSource[T any](aslice []T) Source[T] {
+	return func(consumer Consumer[T]) error {
+		return FromSlice(aslice, consumer)
+	}
+}
+
+// PtrSliceSource returns a Source that sends the values in aslice skipping
+// nil pointers.
+func PtrSliceSource[T any](aslice []*T) Source[T] {
+	return func(consumer Consumer[T]) error {
+		return FromPtrSlice(aslice, consumer)
+	}
+}
+
+// IntGeneratorSource returns a Source that sends the ints from generator.
+// generator returns a negative number when there are no more ints to send.
+func IntGeneratorSource(generator func() int) Source[int] {
+	return func(consumer Consumer[int]) error {
+		return FromIntGenerator(generator, consumer)
+	}
+}
+
+// GeneratorSource returns a Source that sends the values from generator.
+// generator returns false when there are no more values to send.
+func GeneratorSource[T any](generator func() (T, bool)) Source[T] {
+	return func(consumer Consumer[T]) error {
+		return FromGenerator(generator, consumer)
+	}
+}
+
+// Run sends the T values of this source to consumer and returns the error
+// consumer encountered, if any.
+func (s Source[T]) Run(consumer Consumer[T]) error {
+	return s(consumer)
+}
+
+// Collect returns the T values of this source as a slice.
+func (s Source[T]) Collect() ([]T, error) {
+	var result []T
+	err := s(AppendTo(&result))
+	return result, err
+}
+
+// Take returns a Source that sends only the first n T values of this
+// source.
+func (s Source[T]) Take(n int) Source[T] {
+	return Through(s, PSlice[T](0, n))
+}
+
+// WithContext returns a Source that stops sending T values once ctx is
+// done. In that case, the returned source reports ctx.Err().
+func (s Source[T]) WithContext(ctx context.Context) Source[T] {
+	return func(consumer Consumer[T]) error {
+		return s(WithContext(ctx, consumer))
+	}
+}
+
+// Through returns a Source that sends the U values that pipeline emits
+// when it consumes the T values of source.
+func Through[T, U any](source Source[T], pipeline Pipeline[T, U]) Source[U] {
+	return func(consumer Consumer[U]) error {
+		return source(pipeline(consumer))
+	}
+}
+
+// Concat returns a Source that sends the values of each source in sources
+// one after the other. The returned source calls Finish on its consumer
+// only once after the last source is done.
+func Concat[T any](sources ...Source[T]) Source[T] {
+	sourceList := make([]Source[T], len(sources))
+	copy(sourceList, sources)
+	return func(consumer Consumer[T]) error {
+		unfinished := unfinishedConsumer[T]{Consumer: consumer}
+		for _, source := range sourceList {
+			if !consumer.CanConsume() {
+				break
+			}
+			if err := source(unfinished); err != nil {
+				break
+			}
+		}
+		Finish(consumer)
+		return Err(consumer)
+	}
+}
+
+type unfinishedConsumer[T any] struct {
+	Consumer[T]
+}
+
+func (u unfinishedConsumer[T]) Err() error {
+	return Err(u.Consumer)
+}
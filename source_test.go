@@ -0,0 +1,80 @@
+package consume2_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSources(t *testing.T) {
+	assert := assert.New(t)
+	values, err := consume2.SliceSource([]int{1, 2, 3}).Collect()
+	assert.NoError(err)
+	assert.Equal([]int{1, 2, 3}, values)
+	one, two := 1, 2
+	values, err = consume2.PtrSliceSource([]*int{&one, nil, &two}).Collect()
+	assert.NoError(err)
+	assert.Equal([]int{1, 2}, values)
+	values, err = consume2.IntGeneratorSource(squaresLessThan36()).Collect()
+	assert.NoError(err)
+	assert.Equal([]int{1, 4, 9, 16, 25}, values)
+	values, err = consume2.GeneratorSource(cubesLessThan216()).Collect()
+	assert.NoError(err)
+	assert.Equal([]int{1, 8, 27, 64, 125}, values)
+}
+
+func TestSourceThroughAndTake(t *testing.T) {
+	assert := assert.New(t)
+	source := consume2.Through(
+		consume2.GeneratorSource(cubesLessThan216()),
+		consume2.PMap(strconv.Itoa))
+	values, err := source.Take(3).Collect()
+	assert.NoError(err)
+	assert.Equal([]string{"1", "8", "27"}, values)
+}
+
+func TestSourceConcat(t *testing.T) {
+	assert := assert.New(t)
+	source := consume2.Concat(
+		consume2.SliceSource([]int{1, 2}),
+		consume2.SliceSource[int](nil),
+		consume2.SliceSource([]int{3, 4}),
+		consume2.GeneratorSource(cubesLessThan216()))
+	var tracker finishTracker[int]
+	assert.NoError(source.Run(consume2.Slice[int](&tracker, 0, 6)))
+	assert.Equal([]int{1, 2, 3, 4, 1, 8}, tracker.values)
+	assert.Equal(1, tracker.finished)
+}
+
+func TestSourceConcatErr(t *testing.T) {
+	assert := assert.New(t)
+	errBad := errors.New("bad")
+	source := consume2.Concat(
+		consume2.SliceSource([]int{1, 2}),
+		consume2.SliceSource([]int{-1, 3}),
+		consume2.SliceSource([]int{4}))
+	var result []int
+	err := source.Run(consume2.MapErr(
+		consume2.AppendTo(&result),
+		func(x int) (int, error) {
+			if x < 0 {
+				return 0, errBad
+			}
+			return x, nil
+		}))
+	assert.Equal(errBad, err)
+	assert.Equal([]int{1, 2}, result)
+}
+
+func TestSourceWithContext(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	values, err := consume2.SliceSource([]int{1, 2}).WithContext(ctx).Collect()
+	assert.Equal(context.Canceled, err)
+	assert.Empty(values)
+}
@@ -0,0 +1,151 @@
+package consume2
+
+import (
+	"math"
+)
+
+// Stats[N] is a Consumer[N] that computes descriptive statistics of the N
+// values it consumes in a single pass without storing them. Stats[N] uses
+// numerically stable updates in the style of Welford's algorithm.
+// The zero value of Stats[N] is ready to use.
+type Stats[N Number] struct {
+	count int
+	mean  float64
+	m2    float64
+	m3    float64
+	m4    float64
+	min   N
+	max   N
+}
+
+// CanConsume always returns true.
+func (s *Stats[N]) CanConsume() bool {
+	return true
+}
+
+// Consume includes value in the statistics.
+func (s *Stats[N]) Consume(value N) {
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	n1 := float64(s.count)
+	s.count++
+	n := float64(s.count)
+	delta := float64(value) - s.mean
+	deltaN := delta / n
+	deltaN2 := deltaN * deltaN
+	term1 := delta * deltaN * n1
+	s.mean += deltaN
+	s.m4 += term1*deltaN2*(n*n-3*n+3) + 6*deltaN2*s.m2 - 4*deltaN*s.m3
+	s.m3 += term1*deltaN*(n-2) - 3*deltaN*s.m2
+	s.m2 += term1
+}
+
+// Merge combines the statistics in other into this instance as if this
+// instance had also consumed the N values that other consumed. Merge
+// allows computing statistics in shards and combining them afterwards.
+func (s *Stats[N]) Merge(other *Stats[N]) {
+	if other.count == 0 {
+		return
+	}
+	if s.count == 0 {
+		*s = *other
+		return
+	}
+	na := float64(s.count)
+	nb := float64(other.count)
+	n := na + nb
+	delta := other.mean - s.mean
+	delta2 := delta * delta
+	delta3 := delta2 * delta
+	delta4 := delta2 * delta2
+	m2 := s.m2 + other.m2 + delta2*na*nb/n
+	m3 := s.m3 + other.m3 +
+		delta3*na*nb*(na-nb)/(n*n) +
+		3*delta*(na*other.m2-nb*s.m2)/n
+	m4 := s.m4 + other.m4 +
+		delta4*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*delta2*(na*na*other.m2+nb*nb*s.m2)/(n*n) +
+		4*delta*(na*other.m3-nb*s.m3)/n
+	s.mean += delta * nb / n
+	s.m2, s.m3, s.m4 = m2, m3, m4
+	s.count += other.count
+	if other.min < s.min {
+		s.min = other.min
+	}
+	if other.max > s.max {
+		s.max = other.max
+	}
+}
+
+// Count returns the number of N values consumed.
+func (s *Stats[N]) Count() int {
+	return s.count
+}
+
+// Min returns the smallest N value consumed or 0 if no values were
+// consumed.
+func (s *Stats[N]) Min() N {
+	return s.min
+}
+
+// Max returns the largest N value consumed or 0 if no values were
+// consumed.
+func (s *Stats[N]) Max() N {
+	return s.max
+}
+
+// Mean returns the arithmetic mean of the N values consumed or NaN if no
+// values were consumed.
+func (s *Stats[N]) Mean() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+// Variance returns the sample variance of the N values consumed or NaN if
+// fewer than two values were consumed.
+func (s *Stats[N]) Variance() float64 {
+	if s.count < 2 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.count-1)
+}
+
+// PopulationVariance returns the population variance of the N values
+// consumed or NaN if no values were consumed.
+func (s *Stats[N]) PopulationVariance() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	return s.m2 / float64(s.count)
+}
+
+// StdDev returns the sample standard deviation of the N values consumed or
+// NaN if fewer than two values were consumed.
+func (s *Stats[N]) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Skewness returns the population skewness of the N values consumed. It
+// returns NaN if no values were consumed or if all the values are equal.
+func (s *Stats[N]) Skewness() float64 {
+	if s.count == 0 || s.m2 == 0 {
+		return math.NaN()
+	}
+	return math.Sqrt(float64(s.count)) * s.m3 / math.Pow(s.m2, 1.5)
+}
+
+// Kurtosis returns the population excess kurtosis of the N values
+// consumed. It returns NaN if no values were consumed or if all the values
+// are equal.
+func (s *Stats[N]) Kurtosis() float64 {
+	if s.count == 0 || s.m2 == 0 {
+		return math.NaN()
+	}
+	return float64(s.count)*s.m4/(s.m2*s.m2) - 3
+}
@@ -0,0 +1,85 @@
+package consume2_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+var statsValues = []float64{2, 4, 4, 4, 5, 5, 7, 9, 12, 1e3}
+
+func TestStats(t *testing.T) {
+	assert := assert.New(t)
+	var stats consume2.Stats[float64]
+	consume2.FromSlice[float64](statsValues, &stats)
+	assertStats(t, statsValues, &stats)
+	assert.Equal(2.0, stats.Min())
+	assert.Equal(1e3, stats.Max())
+}
+
+func TestStatsMerge(t *testing.T) {
+	assert := assert.New(t)
+	var first, second, empty consume2.Stats[float64]
+	consume2.FromSlice[float64](statsValues[:3], &first)
+	consume2.FromSlice[float64](statsValues[3:], &second)
+	first.Merge(&empty)
+	first.Merge(&second)
+	assertStats(t, statsValues, &first)
+	empty.Merge(&first)
+	assertStats(t, statsValues, &empty)
+	assert.Equal(2.0, empty.Min())
+	assert.Equal(1e3, empty.Max())
+}
+
+func TestStatsLargeOffset(t *testing.T) {
+	assert := assert.New(t)
+	var stats consume2.Stats[float64]
+	consume2.FromSlice[float64](
+		[]float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16}, &stats)
+	assert.InDelta(1e9+10, stats.Mean(), 1e-6)
+	assert.InDelta(30.0, stats.Variance(), 1e-6)
+}
+
+func TestStatsEmpty(t *testing.T) {
+	assert := assert.New(t)
+	var stats consume2.Stats[int]
+	assert.Zero(stats.Count())
+	assert.True(math.IsNaN(stats.Mean()))
+	assert.True(math.IsNaN(stats.Variance()))
+	assert.True(math.IsNaN(stats.Skewness()))
+	assert.True(math.IsNaN(stats.Kurtosis()))
+	stats.Consume(3)
+	assert.Equal(3.0, stats.Mean())
+	assert.Zero(stats.PopulationVariance())
+	assert.True(math.IsNaN(stats.Variance()))
+	assert.Equal(3, stats.Min())
+	assert.Equal(3, stats.Max())
+}
+
+func assertStats(
+	t *testing.T, values []float64, stats *consume2.Stats[float64]) {
+	t.Helper()
+	n := float64(len(values))
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= n
+	var m2, m3, m4 float64
+	for _, v := range values {
+		d := v - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	assert.Equal(t, len(values), stats.Count())
+	assert.InDelta(t, mean, stats.Mean(), 1e-9)
+	assert.InDelta(t, m2/(n-1), stats.Variance(), 1e-6)
+	assert.InDelta(t, math.Sqrt(m2/(n-1)), stats.StdDev(), 1e-9)
+	assert.InDelta(t, m2/n, stats.PopulationVariance(), 1e-6)
+	assert.InDelta(
+		t, math.Sqrt(n)*m3/math.Pow(m2, 1.5), stats.Skewness(), 1e-9)
+	assert.InDelta(t, n*m4/(m2*m2)-3, stats.Kurtosis(), 1e-9)
+}
@@ -0,0 +1,285 @@
+package consume2
+
+import (
+	"time"
+)
+
+// TimeWindow[T] is a window of T values grouped by event time.
+type TimeWindow[T any] struct {
+
+	// Start is the inclusive start of the window.
+	Start time.Time
+
+	// End is the exclusive end of the window.
+	End time.Time
+
+	// Values are the T values in the window in the order consumed. When
+	// session windows merge, the values of the earlier session come first.
+	Values []T
+}
+
+// WindowSpec describes how WindowByTime assigns T values to windows.
+type WindowSpec struct {
+	size  time.Duration
+	slide time.Duration
+	gap   time.Duration
+}
+
+// TumblingWindows returns a WindowSpec for back to back windows of length
+// size. Each T value falls in exactly one window. Windows are aligned to
+// multiples of size since the zero time. TumblingWindows panics if
+// size <= 0.
+func TumblingWindows(size time.Duration) WindowSpec {
+	return SlidingWindows(size, size)
+}
+
+// SlidingWindows returns a WindowSpec for windows of length size with a
+// new window starting every slide. When slide is less than size, windows
+// overlap, and a T value can fall in more than one window. Windows are
+// aligned to multiples of slide since the zero time. SlidingWindows panics
+// if size <= 0 or slide <= 0.
+//
+// When slide is greater than size, there are gaps between windows. T
+// values in a gap belong to no window, so WindowByTime drops them rather
+// than treating them as late.
+func SlidingWindows(size, slide time.Duration) WindowSpec {
+	if size <= 0 {
+		panic("size must be positive")
+	}
+	if slide <= 0 {
+		panic("slide must be positive")
+	}
+	return WindowSpec{size: size, slide: slide}
+}
+
+// SessionWindows returns a WindowSpec for windows that group T values
+// with timestamps less than gap apart. A session window ends gap after the
+// timestamp of its last T value. SessionWindows panics if gap <= 0.
+func SessionWindows(gap time.Duration) WindowSpec {
+	if gap <= 0 {
+		panic("gap must be positive")
+	}
+	return WindowSpec{gap: gap}
+}
+
+// WindowByTime[T] returns a Consumer[T] that groups the T values it
+// consumes into windows by event time as spec describes and sends each
+// window to the underlying consumer once it closes. timestamp returns the
+// event time of a T value.
+//
+// T values may arrive out of order by up to maxDelay. The returned
+// consumer tracks a watermark that trails the latest timestamp seen by
+// maxDelay, and a window closes once the watermark reaches its end.
+// Windows are sent in order of their end times. A T value that arrives
+// after all the windows it belongs to have closed is late and goes to
+// late instead. For session windows, a T value is also late if its session
+// would overlap a session already sent. late may be nil in which case late
+// T values are dropped. When finished, the returned consumer sends all the
+// windows still open.
+// WindowByTime[T] panics if spec is the zero WindowSpec or maxDelay is
+// negative.
+func WindowByTime[T any](
+	consumer Consumer[TimeWindow[T]],
+	timestamp func(T) time.Time,
+	spec WindowSpec,
+	maxDelay time.Duration,
+	late Consumer[T]) Consumer[T] {
+	checkTimeWindow(spec, maxDelay)
+	if late == nil {
+		late = Nil[T]()
+	}
+	return &timeWindowConsumer[T]{
+		consumer:  consumer,
+		timestamp: timestamp,
+		spec:      spec,
+		maxDelay:  maxDelay,
+		late:      late,
+	}
+}
+
+// PTimeWindow returns a Pipeline that groups the T values it receives into
+// windows by event time and emits each window once it closes. late
+// receives the T values that arrive too late for any window. See
+// WindowByTime.
+func PTimeWindow[T any](
+	timestamp func(T) time.Time,
+	spec WindowSpec,
+	maxDelay time.Duration,
+	late Consumer[T]) Pipeline[T, TimeWindow[T]] {
+	checkTimeWindow(spec, maxDelay)
+	return func(inner Consumer[TimeWindow[T]]) Consumer[T] {
+		return WindowByTime(inner, timestamp, spec, maxDelay, late)
+	}
+}
+
+type timeWindowConsumer[T any] struct {
+	consumer  Consumer[TimeWindow[T]]
+	timestamp func(T) time.Time
+	spec      WindowSpec
+	maxDelay  time.Duration
+	late      Consumer[T]
+
+	// windows holds the open windows sorted by start time. For both fixed
+	// size windows and session windows, that also sorts them by end time.
+	windows   []TimeWindow[T]
+	watermark time.Time
+	started   bool
+
+	// emittedEnd is the latest end of any window sent so far. emitted
+	// reports whether any window has been sent.
+	emittedEnd time.Time
+	emitted    bool
+}
+
+func (t *timeWindowConsumer[T]) CanConsume() bool {
+	return t.consumer.CanConsume()
+}
+
+func (t *timeWindowConsumer[T]) Consume(value T) {
+	if !t.consumer.CanConsume() {
+		return
+	}
+	ts := t.timestamp(value)
+	var accepted bool
+	if t.spec.gap > 0 {
+		accepted = t.addToSession(value, ts)
+	} else {
+		accepted = t.addToFixed(value, ts)
+	}
+	if !accepted && t.late.CanConsume() {
+		t.late.Consume(value)
+	}
+	watermark := ts.Add(-t.maxDelay)
+	if !t.started || watermark.After(t.watermark) {
+		t.watermark = watermark
+		t.started = true
+		t.emitWhile(func(w *TimeWindow[T]) bool { return t.closed(w.End) })
+	}
+}
+
+func (t *timeWindowConsumer[T]) Finish() {
+	t.emitWhile(func(w *TimeWindow[T]) bool { return true })
+	t.windows = nil
+	Finish(t.consumer)
+	Finish(t.late)
+}
+
+func (t *timeWindowConsumer[T]) Err() error {
+	if err := Err(t.consumer); err != nil {
+		return err
+	}
+	return Err(t.late)
+}
+
+// closed reports whether a window ending at end is closed.
+func (t *timeWindowConsumer[T]) closed(end time.Time) bool {
+	return t.started && !end.After(t.watermark)
+}
+
+// addToFixed returns false if value belongs to at least one window but
+// all the windows it belongs to have closed.
+func (t *timeWindowConsumer[T]) addToFixed(value T, ts time.Time) bool {
+	var belongs, added bool
+	start := ts.Truncate(t.spec.slide)
+	for ; ts.Before(start.Add(t.spec.size)); start = start.Add(-t.spec.slide) {
+		belongs = true
+		end := start.Add(t.spec.size)
+		if t.closed(end) {
+			continue
+		}
+		index := t.find(start)
+		if index == len(t.windows) || !t.windows[index].Start.Equal(start) {
+			t.insert(index, TimeWindow[T]{Start: start, End: end})
+		}
+		t.windows[index].Values = append(t.windows[index].Values, value)
+		added = true
+	}
+	return added || !belongs
+}
+
+func (t *timeWindowConsumer[T]) addToSession(value T, ts time.Time) bool {
+	session := TimeWindow[T]{Start: ts, End: ts.Add(t.spec.gap)}
+	first := t.find(session.Start)
+	if first > 0 && t.windows[first-1].End.After(session.Start) {
+		first--
+	}
+	last := first
+	for last < len(t.windows) && t.windows[last].Start.Before(session.End) {
+		last++
+	}
+	if t.emitted && session.Start.Before(t.emittedEnd) {
+		return false
+	}
+	if first == last {
+		if t.closed(session.End) {
+			return false
+		}
+		session.Values = []T{value}
+		t.insert(first, session)
+		return true
+	}
+	merged := t.windows[first]
+	for _, w := range t.windows[first+1 : last] {
+		merged.Values = append(merged.Values, w.Values...)
+		if w.End.After(merged.End) {
+			merged.End = w.End
+		}
+	}
+	merged.Values = append(merged.Values, value)
+	if session.Start.Before(merged.Start) {
+		merged.Start = session.Start
+	}
+	if session.End.After(merged.End) {
+		merged.End = session.End
+	}
+	t.windows[first] = merged
+	t.windows = append(t.windows[:first+1], t.windows[last:]...)
+	return true
+}
+
+// find returns the index of the first open window that starts at or
+// after start.
+func (t *timeWindowConsumer[T]) find(start time.Time) int {
+	index := 0
+	for index < len(t.windows) && t.windows[index].Start.Before(start) {
+		index++
+	}
+	return index
+}
+
+func (t *timeWindowConsumer[T]) insert(index int, w TimeWindow[T]) {
+	t.windows = append(t.windows, TimeWindow[T]{})
+	copy(t.windows[index+1:], t.windows[index:])
+	t.windows[index] = w
+}
+
+func (t *timeWindowConsumer[T]) emitWhile(f func(w *TimeWindow[T]) bool) {
+	index := 0
+	for index < len(t.windows) && f(&t.windows[index]) {
+		if t.consumer.CanConsume() {
+			t.consumer.Consume(t.windows[index])
+		}
+		if !t.emitted || t.windows[index].End.After(t.emittedEnd) {
+			t.emittedEnd = t.windows[index].End
+			t.emitted = true
+		}
+		index++
+	}
+	if index == 0 {
+		return
+	}
+	remaining := copy(t.windows, t.windows[index:])
+	for i := remaining; i < len(t.windows); i++ {
+		t.windows[i] = TimeWindow[T]{}
+	}
+	t.windows = t.windows[:remaining]
+}
+
+func checkTimeWindow(spec WindowSpec, maxDelay time.Duration) {
+	if spec.gap <= 0 && spec.size <= 0 {
+		panic("spec must be set")
+	}
+	if maxDelay < 0 {
+		panic("maxDelay must be non-negative")
+	}
+}
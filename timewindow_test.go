@@ -0,0 +1,207 @@
+package consume2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+type event struct {
+	Second int
+	Name   string
+}
+
+func eventTime(e event) time.Time {
+	return time.Unix(int64(e.Second), 0).UTC()
+}
+
+type windowSummary struct {
+	Start int
+	End   int
+	Names []string
+}
+
+func summarize(window consume2.TimeWindow[event]) windowSummary {
+	result := windowSummary{
+		Start: int(window.Start.Unix()),
+		End:   int(window.End.Unix()),
+	}
+	for _, e := range window.Values {
+		result.Names = append(result.Names, e.Name)
+	}
+	return result
+}
+
+func TestPTimeWindowTumbling(t *testing.T) {
+	assert := assert.New(t)
+	var late []event
+	var windows []windowSummary
+	var closedEarly []windowSummary
+	pipeline := consume2.Join(
+		consume2.PTimeWindow(
+			eventTime,
+			consume2.TumblingWindows(10*time.Second),
+			3*time.Second,
+			consume2.AppendTo(&late)),
+		consume2.PMap(summarize))
+	consumer := pipeline.AppendTo(&windows)
+	for _, e := range []event{
+		{Second: 1, Name: "a"},
+		{Second: 12, Name: "b"},
+		{Second: 9, Name: "c"},
+		{Second: 14, Name: "d"},
+		{Second: 8, Name: "e"},
+		{Second: 21, Name: "f"},
+		{Second: 25, Name: "g"},
+	} {
+		consumer.Consume(e)
+	}
+	closedEarly = append(closedEarly, windows...)
+	consume2.Finish(consumer)
+	assert.Equal(
+		[]windowSummary{
+			{Start: 0, End: 10, Names: []string{"a", "c"}},
+			{Start: 10, End: 20, Names: []string{"b", "d"}},
+		},
+		closedEarly)
+	assert.Equal(
+		[]windowSummary{
+			{Start: 0, End: 10, Names: []string{"a", "c"}},
+			{Start: 10, End: 20, Names: []string{"b", "d"}},
+			{Start: 20, End: 30, Names: []string{"f", "g"}},
+		},
+		windows)
+	assert.Equal([]event{{Second: 8, Name: "e"}}, late)
+}
+
+func TestPTimeWindowSliding(t *testing.T) {
+	assert := assert.New(t)
+	var windows []windowSummary
+	pipeline := consume2.Join(
+		consume2.PTimeWindow(
+			eventTime,
+			consume2.SlidingWindows(10*time.Second, 5*time.Second),
+			0,
+			nil),
+		consume2.PMap(summarize))
+	consume2.FromSlice(
+		[]event{
+			{Second: 3, Name: "a"},
+			{Second: 7, Name: "b"},
+			{Second: 12, Name: "c"},
+			{Second: 4, Name: "late"},
+		},
+		pipeline.AppendTo(&windows))
+	assert.Equal(
+		[]windowSummary{
+			{Start: -5, End: 5, Names: []string{"a"}},
+			{Start: 0, End: 10, Names: []string{"a", "b"}},
+			{Start: 5, End: 15, Names: []string{"b", "c"}},
+			{Start: 10, End: 20, Names: []string{"c"}},
+		},
+		windows)
+}
+
+func TestPTimeWindowHopping(t *testing.T) {
+	assert := assert.New(t)
+	var late []event
+	var windows []windowSummary
+	pipeline := consume2.Join(
+		consume2.PTimeWindow(
+			eventTime,
+			consume2.SlidingWindows(2*time.Second, 5*time.Second),
+			0,
+			consume2.AppendTo(&late)),
+		consume2.PMap(summarize))
+	consume2.FromSlice(
+		[]event{
+			{Second: 1, Name: "a"},
+			{Second: 3, Name: "b"},
+			{Second: 6, Name: "c"},
+			{Second: 12, Name: "d"},
+			{Second: 0, Name: "e"},
+		},
+		pipeline.AppendTo(&windows))
+	assert.Equal(
+		[]windowSummary{
+			{Start: 0, End: 2, Names: []string{"a"}},
+			{Start: 5, End: 7, Names: []string{"c"}},
+		},
+		windows)
+	assert.Equal([]event{{Second: 0, Name: "e"}}, late)
+}
+
+func TestPTimeWindowSession(t *testing.T) {
+	assert := assert.New(t)
+	var late []event
+	var windows []windowSummary
+	pipeline := consume2.Join(
+		consume2.PTimeWindow(
+			eventTime,
+			consume2.SessionWindows(5*time.Second),
+			2*time.Second,
+			consume2.AppendTo(&late)),
+		consume2.PMap(summarize))
+	consume2.FromSlice(
+		[]event{
+			{Second: 1, Name: "a"},
+			{Second: 10, Name: "b"},
+			{Second: 4, Name: "c"},
+			{Second: 7, Name: "d"},
+			{Second: 30, Name: "e"},
+			{Second: 20, Name: "f"},
+			{Second: 31, Name: "g"},
+		},
+		pipeline.AppendTo(&windows))
+	assert.Equal(
+		[]windowSummary{
+			{Start: 1, End: 6, Names: []string{"a"}},
+			{Start: 7, End: 15, Names: []string{"b", "d"}},
+			{Start: 30, End: 36, Names: []string{"e", "g"}},
+		},
+		windows)
+	assert.Equal(
+		[]event{{Second: 4, Name: "c"}, {Second: 20, Name: "f"}}, late)
+}
+
+func TestPTimeWindowSessionOverlapsEmitted(t *testing.T) {
+	assert := assert.New(t)
+	var late []event
+	var windows []windowSummary
+	pipeline := consume2.Join(
+		consume2.PTimeWindow(
+			eventTime,
+			consume2.SessionWindows(10*time.Second),
+			0,
+			consume2.AppendTo(&late)),
+		consume2.PMap(summarize))
+	consume2.FromSlice(
+		[]event{
+			{Second: 0, Name: "a"},
+			{Second: 12, Name: "b"},
+			{Second: 8, Name: "c"},
+		},
+		pipeline.AppendTo(&windows))
+	assert.Equal(
+		[]windowSummary{
+			{Start: 0, End: 10, Names: []string{"a"}},
+			{Start: 12, End: 22, Names: []string{"b"}},
+		},
+		windows)
+	assert.Equal([]event{{Second: 8, Name: "c"}}, late)
+}
+
+func TestTimeWindowPanics(t *testing.T) {
+	assert.Panics(t, func() { consume2.TumblingWindows(0) })
+	assert.Panics(t, func() { consume2.SlidingWindows(time.Second, 0) })
+	assert.Panics(t, func() { consume2.SessionWindows(-time.Second) })
+	assert.Panics(t, func() {
+		consume2.PTimeWindow(eventTime, consume2.WindowSpec{}, 0, nil)
+	})
+	assert.Panics(t, func() {
+		consume2.PTimeWindow(
+			eventTime, consume2.TumblingWindows(time.Second), -1, nil)
+	})
+}
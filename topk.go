@@ -0,0 +1,87 @@
+package consume2
+
+import (
+	"sort"
+)
+
+// TopK[T] is a Consumer[T] that keeps only the k greatest T values it
+// consumes. TopK[T] uses a heap, so its memory use is proportional to k
+// no matter how many values it consumes.
+type TopK[T any] struct {
+	heap  heap[topKEntry[T]]
+	k     int
+	count int
+}
+
+// NewTopK[T] creates a TopK[T] that keeps the k greatest T values. less
+// reports whether a is less than b. When there are ties, NewTopK[T] favors
+// the T values consumed first. NewTopK[T] panics if k <= 0.
+func NewTopK[T any](k int, less func(a, b T) bool) *TopK[T] {
+	if k <= 0 {
+		panic("k must be positive")
+	}
+	return &TopK[T]{
+		heap: heap[topKEntry[T]]{
+			// The first entry in the heap is the one to evict next. Among
+			// equal values, that is the one consumed last.
+			less: func(a, b topKEntry[T]) bool {
+				if less(a.value, b.value) {
+					return true
+				}
+				if less(b.value, a.value) {
+					return false
+				}
+				return a.seq > b.seq
+			},
+		},
+		k: k,
+	}
+}
+
+// NewBottomK[T] works like NewTopK[T] except that the returned instance
+// keeps the k smallest T values.
+func NewBottomK[T any](k int, less func(a, b T) bool) *TopK[T] {
+	return NewTopK(k, func(a, b T) bool { return less(b, a) })
+}
+
+// CanConsume always returns true.
+func (t *TopK[T]) CanConsume() bool {
+	return true
+}
+
+// Consume consumes a single T value.
+func (t *TopK[T]) Consume(value T) {
+	entry := topKEntry[T]{value: value, seq: t.count}
+	t.count++
+	if t.heap.Len() < t.k {
+		t.heap.Push(entry)
+		return
+	}
+	if t.heap.less(t.heap.First(), entry) {
+		t.heap.ReplaceFirst(entry)
+	}
+}
+
+// Build returns the kept T values best first. For an instance from
+// NewTopK[T] that means greatest first; for an instance from
+// NewBottomK[T] that means smallest first. Among equal T values, the ones
+// consumed first come first. Build returns fewer than k values if fewer
+// than k values were consumed.
+func (t *TopK[T]) Build() []T {
+	entries := make([]topKEntry[T], len(t.heap.values))
+	copy(entries, t.heap.values)
+	less := t.heap.less
+	sort.Slice(entries, func(i, j int) bool {
+		return less(entries[j], entries[i])
+	})
+	result := make([]T, len(entries))
+	for i := range entries {
+		result[i] = entries[i].value
+	}
+	return result
+}
+
+type topKEntry[T any] struct {
+	value T
+	seq   int
+}
@@ -0,0 +1,84 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK(t *testing.T) {
+	assert := assert.New(t)
+	topK := consume2.NewTopK(3, func(a, b int) bool { return a < b })
+	consume2.FromSlice[int](shuffledInts(1000, 4), topK)
+	assert.Equal([]int{999, 998, 997}, topK.Build())
+}
+
+func TestBottomK(t *testing.T) {
+	assert := assert.New(t)
+	bottomK := consume2.NewBottomK(4, func(a, b int) bool { return a < b })
+	consume2.FromSlice[int](shuffledInts(1000, 5), bottomK)
+	assert.Equal([]int{0, 1, 2, 3}, bottomK.Build())
+}
+
+func TestTopKFewValues(t *testing.T) {
+	assert := assert.New(t)
+	topK := consume2.NewTopK(5, func(a, b int) bool { return a < b })
+	assert.Empty(topK.Build())
+	consume2.FromSlice[int]([]int{3, 1, 2}, topK)
+	assert.Equal([]int{3, 2, 1}, topK.Build())
+}
+
+func TestTopKTies(t *testing.T) {
+	assert := assert.New(t)
+	topK := consume2.NewTopK(
+		2, func(a, b person) bool { return a.Age < b.Age })
+	consume2.FromSlice[person]([]person{
+		{Name: "a", Age: 5},
+		{Name: "b", Age: 9},
+		{Name: "c", Age: 5},
+		{Name: "d", Age: 1},
+	}, topK)
+	assert.Equal(
+		[]person{{Name: "b", Age: 9}, {Name: "a", Age: 5}}, topK.Build())
+}
+
+func TestTopKEvictsLaterTie(t *testing.T) {
+	assert := assert.New(t)
+	topK := consume2.NewTopK(
+		2, func(a, b person) bool { return a.Age < b.Age })
+	consume2.FromSlice[person]([]person{
+		{Name: "a", Age: 5},
+		{Name: "b", Age: 5},
+		{Name: "c", Age: 9},
+	}, topK)
+	assert.Equal(
+		[]person{{Name: "c", Age: 9}, {Name: "a", Age: 5}}, topK.Build())
+}
+
+func TestTopKBuildOrdersTies(t *testing.T) {
+	assert := assert.New(t)
+	bottomK := consume2.NewBottomK(
+		4, func(a, b person) bool { return a.Age < b.Age })
+	consume2.FromSlice[person]([]person{
+		{Name: "a", Age: 5},
+		{Name: "b", Age: 1},
+		{Name: "c", Age: 5},
+		{Name: "d", Age: 1},
+		{Name: "e", Age: 5},
+	}, bottomK)
+	assert.Equal(
+		[]person{
+			{Name: "b", Age: 1},
+			{Name: "d", Age: 1},
+			{Name: "a", Age: 5},
+			{Name: "c", Age: 5},
+		},
+		bottomK.Build())
+}
+
+func TestNewTopKPanics(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	assert.Panics(t, func() { consume2.NewTopK(0, less) })
+	assert.Panics(t, func() { consume2.NewBottomK(-1, less) })
+}
@@ -0,0 +1,118 @@
+package consume2
+
+// Window[T] returns a Consumer[T] that sends windows of size consecutive T
+// values to the underlying consumer. Each window starts step T values
+// after the previous one. When step equals size, the windows are
+// tumbling; when step is less than size, they are sliding and overlap;
+// when step is greater than size, they are hopping and some T values fall
+// in no window. Only full windows are sent. Each window sent is a new
+// slice. Window[T] panics if size <= 0 or step <= 0.
+func Window[T any](consumer Consumer[[]T], size, step int) Consumer[T] {
+	return WindowReduce(consumer, size, step, copyWindow[T])
+}
+
+// WindowReduce[T,U] works like Window[T] except that it applies reduce to
+// each window and sends the resulting U value to the underlying consumer.
+// reduce may not hold onto the slice passed to it as its contents change
+// afterwards. Computing moving averages or moving maximums this way does
+// not allocate memory per T value. WindowReduce[T,U] panics if size <= 0
+// or step <= 0.
+func WindowReduce[T, U any](
+	consumer Consumer[U], size, step int, reduce func([]T) U) Consumer[T] {
+	checkWindow(size, step)
+	return &windowConsumer[T, U]{
+		consumer: consumer,
+		reduce:   reduce,
+		size:     size,
+		step:     step,
+		buffer:   make([]T, 2*size),
+	}
+}
+
+// PWindow returns a Pipeline that emits windows of size consecutive T
+// values with each window starting step T values after the previous one.
+// See Window.
+func PWindow[T any](size, step int) Pipeline[T, []T] {
+	checkWindow(size, step)
+	return func(inner Consumer[[]T]) Consumer[T] {
+		return Window(inner, size, step)
+	}
+}
+
+// PWindowReduce returns a Pipeline that applies reduce to windows of size
+// consecutive T values and emits the resulting U values. See
+// WindowReduce.
+func PWindowReduce[T, U any](
+	size, step int, reduce func([]T) U) Pipeline[T, U] {
+	checkWindow(size, step)
+	return func(inner Consumer[U]) Consumer[T] {
+		return WindowReduce(inner, size, step, reduce)
+	}
+}
+
+// windowConsumer keeps its window in a ring buffer of twice the window
+// size that stores each T value twice. This way the current window is
+// always a contiguous slice of the buffer.
+type windowConsumer[T, U any] struct {
+	consumer Consumer[U]
+	reduce   func([]T) U
+	size     int
+	step     int
+	buffer   []T
+	start    int
+	length   int
+	skip     int
+}
+
+func (w *windowConsumer[T, U]) CanConsume() bool {
+	return w.consumer.CanConsume()
+}
+
+func (w *windowConsumer[T, U]) Consume(value T) {
+	if !w.consumer.CanConsume() {
+		return
+	}
+	if w.skip > 0 {
+		w.skip--
+		return
+	}
+	index := (w.start + w.length) % w.size
+	w.buffer[index] = value
+	w.buffer[index+w.size] = value
+	w.length++
+	if w.length < w.size {
+		return
+	}
+	w.consumer.Consume(w.reduce(w.buffer[w.start : w.start+w.size]))
+	if w.step >= w.size {
+		w.start = 0
+		w.length = 0
+		w.skip = w.step - w.size
+		return
+	}
+	w.start = (w.start + w.step) % w.size
+	w.length -= w.step
+}
+
+func (w *windowConsumer[T, U]) Finish() {
+	Finish(w.consumer)
+}
+
+func (w *windowConsumer[T, U]) Err() error {
+	return Err(w.consumer)
+}
+
+func copyWindow[T any](window []T) []T {
+	result := make([]T, len(window))
+	copy(result, window)
+	return result
+}
+
+func checkWindow(size, step int) {
+	if size <= 0 {
+		panic("size must be positive")
+	}
+	if step <= 0 {
+		panic("step must be positive")
+	}
+}
@@ -0,0 +1,83 @@
+package consume2_test
+
+import (
+	"testing"
+
+	"github.com/keep94/consume2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPWindowSliding(t *testing.T) {
+	assert := assert.New(t)
+	var windows [][]int
+	consume2.FromSlice(
+		[]int{1, 2, 3, 4, 5}, consume2.PWindow[int](3, 1).AppendTo(&windows))
+	assert.Equal([][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, windows)
+}
+
+func TestPWindowTumbling(t *testing.T) {
+	assert := assert.New(t)
+	var windows [][]int
+	consume2.FromSlice(
+		[]int{1, 2, 3, 4, 5, 6, 7},
+		consume2.PWindow[int](3, 3).AppendTo(&windows))
+	assert.Equal([][]int{{1, 2, 3}, {4, 5, 6}}, windows)
+}
+
+func TestPWindowOverlapping(t *testing.T) {
+	assert := assert.New(t)
+	var windows [][]int
+	consume2.FromSlice(
+		[]int{1, 2, 3, 4, 5, 6, 7, 8},
+		consume2.PWindow[int](4, 2).AppendTo(&windows))
+	assert.Equal([][]int{{1, 2, 3, 4}, {3, 4, 5, 6}, {5, 6, 7, 8}}, windows)
+}
+
+func TestPWindowHopping(t *testing.T) {
+	assert := assert.New(t)
+	var windows [][]int
+	consume2.FromSlice(
+		[]int{1, 2, 3, 4, 5, 6, 7, 8, 9},
+		consume2.PWindow[int](2, 4).AppendTo(&windows))
+	assert.Equal([][]int{{1, 2}, {5, 6}}, windows)
+}
+
+func TestPWindowReduce(t *testing.T) {
+	assert := assert.New(t)
+	movingAverage := consume2.PWindowReduce(
+		3,
+		1,
+		func(window []float64) float64 {
+			var sum float64
+			for _, x := range window {
+				sum += x
+			}
+			return sum / float64(len(window))
+		})
+	var averages []float64
+	consume2.FromSlice(
+		[]float64{3, 6, 9, 3, 0, 3}, movingAverage.AppendTo(&averages))
+	assert.Equal([]float64{6, 6, 4, 2}, averages)
+	movingMax := consume2.Join(
+		consume2.PWindowReduce(
+			2,
+			1,
+			func(window []int) int {
+				result := window[0]
+				for _, x := range window[1:] {
+					if x > result {
+						result = x
+					}
+				}
+				return result
+			}),
+		consume2.PSlice[int](0, 3))
+	var maxes []int
+	feedInts(movingMax.AppendTo(&maxes))
+	assert.Equal([]int{1, 2, 3}, maxes)
+}
+
+func TestWindowPanics(t *testing.T) {
+	assert.Panics(t, func() { consume2.PWindow[int](0, 1) })
+	assert.Panics(t, func() { consume2.PWindow[int](1, 0) })
+}